@@ -3,5 +3,7 @@ package main
 import "errors"
 
 var (
-	ErrJwtEnvVarNotSet = errors.New("JWT_SECRET env variable not set")
+	ErrJwtEnvVarNotSet       = errors.New("JWT_SECRET env variable not set")
+	ErrUnknownCacheBackend   = errors.New("unknown cache backend")
+	ErrUnknownBannerMatching = errors.New("unknown banner matching mode")
 )
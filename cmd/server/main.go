@@ -15,30 +15,40 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
 	chimiddlewares "github.com/go-chi/chi/v5/middleware"
-	gocache "github.com/patrickmn/go-cache"
 	httpswagger "github.com/swaggo/http-swagger"
 
+	"avito-backend-trainee-2024/pkg/cache"
 	router "avito-backend-trainee-2024/pkg/route"
+	handlerutils "avito-backend-trainee-2024/pkg/utils/handler"
 
 	bannerrepo "avito-backend-trainee-2024/internal/repository/postgres/banner"
 	featurerepo "avito-backend-trainee-2024/internal/repository/postgres/feature"
+	jobrepo "avito-backend-trainee-2024/internal/repository/postgres/job"
 	tagrepo "avito-backend-trainee-2024/internal/repository/postgres/tag"
 	userrepo "avito-backend-trainee-2024/internal/repository/postgres/user"
 
 	authservice "avito-backend-trainee-2024/internal/service/auth"
 	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+	featureservice "avito-backend-trainee-2024/internal/service/feature"
+	jobservice "avito-backend-trainee-2024/internal/service/job"
+	tagservice "avito-backend-trainee-2024/internal/service/tag"
 
 	midlewares "avito-backend-trainee-2024/internal/handler/middleware"
 
 	authhandler "avito-backend-trainee-2024/internal/handler/auth"
 	adminbannerhandler "avito-backend-trainee-2024/internal/handler/banner/admin"
 	userbannerhandler "avito-backend-trainee-2024/internal/handler/banner/user"
+	featurehandler "avito-backend-trainee-2024/internal/handler/feature"
+	jobhandler "avito-backend-trainee-2024/internal/handler/job"
+	taghandler "avito-backend-trainee-2024/internal/handler/tag"
 
 	"avito-backend-trainee-2024/internal/config"
+	"avito-backend-trainee-2024/internal/domain/entity"
 	"avito-backend-trainee-2024/pkg/hasher"
 
 	_ "avito-backend-trainee-2024/docs"
@@ -47,6 +57,8 @@ import (
 
 const (
 	configPath = "./config"
+	// freshnessBudget is a max age of banner shown to users, server side cache and clients cache share it
+	freshnessBudget = 5 * time.Minute
 )
 
 func initConfig() (*config.Config, error) {
@@ -72,6 +84,11 @@ func initConfig() (*config.Config, error) {
 	viper.SetEnvPrefix("avito_trainee")
 	viper.AutomaticEnv()
 
+	// redis password may be kept out of config file
+	if password := viper.GetString("REDIS_PASSWORD"); password != "" {
+		conf.Cache.Redis.Password = password
+	}
+
 	conf.Jwt.Secret = viper.GetString("JWT_SECRET")
 	if conf.Jwt.Secret == "" {
 		return nil, ErrJwtEnvVarNotSet
@@ -80,9 +97,42 @@ func initConfig() (*config.Config, error) {
 	return &conf, nil
 }
 
+func initCache(conf config.Cache, logger *logrus.Logger) (cache.Cache, error) {
+	expiration := time.Duration(conf.Expiration) * time.Minute
+
+	switch conf.Backend {
+	case config.CacheBackendInMem, "":
+		return cache.NewInMem(expiration, time.Duration(conf.CleanupInterval)*time.Minute), nil
+	case config.CacheBackendLRU:
+		return cache.NewLRU(conf.Size, expiration)
+	case config.CacheBackendRedis:
+		client := redis.NewClient(&redis.Options{
+			Addr:     conf.Redis.Addr,
+			Password: conf.Redis.Password,
+			DB:       conf.Redis.DB,
+		})
+
+		return cache.NewRedis(client, conf.Redis.KeyPrefix, expiration, logger), nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnknownCacheBackend, conf.Backend)
+	}
+}
+
+func initBannerMatching(conf config.BannerMatching) (entity.BannerMatching, error) {
+	switch conf.Mode {
+	case "":
+		return entity.BannerMatchingExact, nil
+	case entity.BannerMatchingExact, entity.BannerMatchingBestSubset, entity.BannerMatchingAnyTag:
+		return conf.Mode, nil
+	default:
+		return "", fmt.Errorf("%w: %v", ErrUnknownBannerMatching, conf.Mode)
+	}
+}
+
 func main() {
 	logger := logrus.New()
 	valid := validator.New(validator.WithRequiredStructEnabled())
+	valid.RegisterTagNameFunc(handlerutils.JSONTagName)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -91,7 +141,10 @@ func main() {
 		logger.Fatalf("error occurred initializing config: %v", err)
 	}
 
-	cache := gocache.New(time.Duration(conf.Cache.Expiration)*time.Minute, time.Duration(conf.Cache.CleanupInterval)*time.Minute)
+	bannerCache, err := initCache(conf.Cache, logger)
+	if err != nil {
+		logger.Fatalf("error occurred initializing cache: %v", err)
+	}
 
 	var conn *sql.DB
 
@@ -126,25 +179,83 @@ func main() {
 	bannerRepo := bannerrepo.New(db)
 	featureRepo := featurerepo.New(db)
 	tagRepo := tagrepo.New(db)
+	jobRepo := jobrepo.New(db)
+
+	bannerIndex := bannerservice.NewIndex(
+		bannerRepo,
+		time.Duration(conf.BannerIndex.RefreshInterval)*time.Second,
+		time.Duration(conf.BannerIndex.MaxStaleness)*time.Second,
+		logger,
+	)
+
+	// load all banners before serving requests, index retries loading in background if it fails here
+	if err = bannerIndex.Load(ctx); err != nil {
+		logger.Errorf("error occurred loading banner index: %v", err)
+	}
+
+	cachePolicy := bannerservice.CachePolicy{
+		TTL:          time.Duration(conf.Cache.Expiration) * time.Minute,
+		StaleTTL:     time.Duration(conf.Cache.StaleWhileRevalidate) * time.Minute,
+		StaleIfError: time.Duration(conf.Cache.StaleIfError) * time.Minute,
+		NegativeTTL:  time.Duration(conf.Cache.NegativeExpiration) * time.Second,
+		FetchTimeout: time.Duration(conf.Cache.FetchTimeout) * time.Second,
+	}
+
+	breaker := bannerservice.NewBreaker(uint32(conf.Postgres.BreakerFailures), time.Duration(conf.Postgres.BreakerTimeout)*time.Second)
+
+	matching, err := initBannerMatching(conf.BannerMatching)
+	if err != nil {
+		logger.Fatalf("error occurred initializing banner matching: %v", err)
+	}
 
-	bannerService := bannerservice.New(bannerRepo, featureRepo, tagRepo)
+	bannerService := bannerservice.New(
+		bannerRepo, featureRepo, tagRepo, bannerCache, cachePolicy, matching, breaker, bannerIndex, logger,
+	)
+	featureService := featureservice.New(featureRepo, bannerCache)
+	tagService := tagservice.New(tagRepo, bannerCache)
 	authService := authservice.New(userRepo, hasher.New())
+	jobService := jobservice.New(
+		jobRepo, bannerRepo, bannerCache,
+		time.Duration(conf.Jobs.PollInterval)*time.Second, time.Duration(conf.Jobs.LeaseTimeout)*time.Second, logger,
+	)
 
 	authMiddleware := midlewares.JWTAuthentication("token", conf.Jwt.Secret, logger)
 	adminAuthMiddleware := midlewares.AdminAuthorization(logger)
-	cacheMiddleware := midlewares.InMemUserBannerCache(cache, logger)
 
 	authHandler := authhandler.New(authService, conf.Jwt, logger, valid, authMiddleware, adminAuthMiddleware)
-	userBannerHandler := userbannerhandler.New(bannerService, logger, valid, authMiddleware, cacheMiddleware)
-	adminBannerHandler := adminbannerhandler.New(bannerService, logger, valid, authMiddleware, adminAuthMiddleware)
+	userBannerCacheControl := userbannerhandler.CacheControl{
+		MaxAge:               time.Duration(conf.Cache.ClientMaxAge) * time.Second,
+		StaleWhileRevalidate: time.Duration(conf.Cache.ClientStaleWhileRevalidate) * time.Second,
+		StaleIfError:         cachePolicy.StaleIfError,
+		// banner visibility depends on user, so responses for different tokens must not be mixed
+		Vary: []string{"token"},
+	}
+
+	// response may be cached by client when the banner is already at the end of server side stale window.
+	// stale-if-error isn't counted: serving last known banner while database is down is preferred to errors
+	maxBannerAge := cachePolicy.TTL + cachePolicy.StaleTTL +
+		userBannerCacheControl.MaxAge + userBannerCacheControl.StaleWhileRevalidate
+	if maxBannerAge > freshnessBudget {
+		logger.Warnf("users may see banners up to %v old, that exceeds freshness budget of %v", maxBannerAge, freshnessBudget)
+	}
+
+	userBannerHandler := userbannerhandler.New(bannerService, userBannerCacheControl, logger, valid, authMiddleware)
+	adminBannerHandler := adminbannerhandler.New(bannerService, jobService, logger, valid, authMiddleware, adminAuthMiddleware)
+	featureHandler := featurehandler.New(featureService, logger, valid, authMiddleware, adminAuthMiddleware)
+	tagHandler := taghandler.New(tagService, logger, valid, authMiddleware, adminAuthMiddleware)
+	jobHandler := jobhandler.New(jobService, logger, authMiddleware, adminAuthMiddleware)
 
 	routers := make(map[string]chi.Router)
 
 	routers["/user_banner"] = userBannerHandler.Routes()
 	routers["/banner"] = adminBannerHandler.Routes()
+	routers["/feature"] = featureHandler.Routes()
+	routers["/tag"] = tagHandler.Routes()
 	routers["/auth"] = authHandler.Routes()
+	routers["/jobs"] = jobHandler.Routes()
 
 	middlewares := []router.Middleware{
+		chimiddlewares.RequestID,
 		chimiddlewares.Recoverer,
 		chimiddlewares.Logger,
 	}
@@ -161,6 +272,15 @@ func main() {
 		httpswagger.URL(fmt.Sprintf("http://localhost:%v/swagger/doc.json", conf.Server.Port)), // The url pointing to API definition
 	))
 
+	// run deferred jobs in background
+	go jobService.Run(ctx)
+
+	// keep banner index up to date in background
+	go bannerIndex.Run(ctx)
+
+	// evict banners changed by other instances from cache
+	go bannerService.RunChangesListener(ctx, time.Duration(conf.Postgres.Interval)*time.Second)
+
 	logger.Infof("server started at port %v", server.Addr)
 
 	go func() {
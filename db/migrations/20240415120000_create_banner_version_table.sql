@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE banner_version
+(
+    id               bigserial not null primary key,
+    banner_id        integer   not null references banner on delete cascade,
+    version          integer   not null,
+    feature_id       integer   not null,
+    tag_ids          integer[] not null,
+    title            text      not null,
+    text             text      not null,
+    url              text      not null,
+    is_active        boolean   not null,
+    -- priority, default flag and rollout are restored along with the rest of the version
+    priority         integer   not null default 0,
+    is_default       boolean   not null default false,
+    rollout_percent  smallint  not null default 100,
+    rollout_previous jsonb,
+    created_by       integer,
+    created_at       timestamp not null default now(),
+    unique (banner_id, version)
+);
+
+-- existing banners get their current state as the first version, banners without tags too
+INSERT INTO banner_version (banner_id, version, feature_id, tag_ids, title, text, url, is_active, created_at)
+SELECT banner.id,
+       1,
+       banner.feature_id,
+       COALESCE(array_agg(bt.tag_id ORDER BY bt.tag_id) FILTER (WHERE bt.tag_id IS NOT NULL), '{}'),
+       c.title,
+       c.text,
+       c.url,
+       banner.is_active,
+       banner.updated_at
+FROM banner
+         JOIN content c ON c.content_id = banner.content_id
+         LEFT JOIN banner_tag bt ON banner.id = bt.banner_id
+GROUP BY banner.id, c.content_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE banner_version;
+-- +goose StatementEnd
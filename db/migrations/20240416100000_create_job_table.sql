@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE job
+(
+    id         bigserial not null primary key,
+    feature_id integer,
+    tag_id     integer,
+    status     text      not null default 'pending',
+    deleted    integer   not null default 0,
+    error      text      not null default '',
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now()
+);
+
+CREATE INDEX job_status_idx ON job (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE job;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE content
+    ADD COLUMN data jsonb;
+
+UPDATE content
+SET data = jsonb_build_object('title', title, 'text', text, 'url', url);
+
+ALTER TABLE content
+    ALTER COLUMN data SET NOT NULL,
+    DROP COLUMN title,
+    DROP COLUMN text,
+    DROP COLUMN url;
+
+ALTER TABLE banner_version
+    ADD COLUMN content jsonb;
+
+UPDATE banner_version
+SET content = jsonb_build_object('title', title, 'text', text, 'url', url);
+
+ALTER TABLE banner_version
+    ALTER COLUMN content SET NOT NULL,
+    DROP COLUMN title,
+    DROP COLUMN text,
+    DROP COLUMN url;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE content
+    ADD COLUMN title text not null default '',
+    ADD COLUMN text  text not null default '',
+    ADD COLUMN url   text not null default '';
+
+UPDATE content
+SET title = COALESCE(data ->> 'title', ''),
+    text  = COALESCE(data ->> 'text', ''),
+    url   = COALESCE(data ->> 'url', '');
+
+ALTER TABLE content
+    DROP COLUMN data;
+
+ALTER TABLE banner_version
+    ADD COLUMN title text not null default '',
+    ADD COLUMN text  text not null default '',
+    ADD COLUMN url   text not null default '';
+
+UPDATE banner_version
+SET title = COALESCE(content ->> 'title', ''),
+    text  = COALESCE(content ->> 'text', ''),
+    url   = COALESCE(content ->> 'url', '');
+
+ALTER TABLE banner_version
+    DROP COLUMN content;
+-- +goose StatementEnd
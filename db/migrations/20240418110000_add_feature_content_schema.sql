@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- empty schema allows content of any structure
+ALTER TABLE feature
+    ADD COLUMN content_schema jsonb not null default '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE feature
+    DROP COLUMN content_schema;
+-- +goose StatementEnd
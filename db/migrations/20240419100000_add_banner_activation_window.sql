@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE banner
+    ADD COLUMN active_from  timestamptz,
+    ADD COLUMN active_until timestamptz;
+
+ALTER TABLE banner_version
+    ADD COLUMN active_from  timestamptz,
+    ADD COLUMN active_until timestamptz;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE banner
+    DROP COLUMN active_from,
+    DROP COLUMN active_until;
+
+ALTER TABLE banner_version
+    DROP COLUMN active_from,
+    DROP COLUMN active_until;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- feature having banners can be deleted only explicitly deleting its banners first
+ALTER TABLE banner
+    DROP CONSTRAINT banner_feature_id_fkey,
+    ADD CONSTRAINT banner_feature_id_fkey FOREIGN KEY (feature_id) REFERENCES feature ON DELETE RESTRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE banner
+    DROP CONSTRAINT banner_feature_id_fkey,
+    ADD CONSTRAINT banner_feature_id_fkey FOREIGN KEY (feature_id) REFERENCES feature ON DELETE CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- sorted tag ids of the banner, feature and tag set uniquely identify the banner
+ALTER TABLE banner
+    ADD COLUMN tag_ids integer[] not null default '{}';
+
+UPDATE banner
+SET tag_ids = COALESCE((SELECT array_agg(DISTINCT bt.tag_id ORDER BY bt.tag_id)
+                        FROM banner_tag bt
+                        WHERE bt.banner_id = banner.id), '{}');
+
+-- fails if there already are banners with the same feature and tags, they must be resolved manually
+CREATE UNIQUE INDEX banner_feature_id_tag_ids_key ON banner (feature_id, tag_ids);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX banner_feature_id_tag_ids_key;
+
+ALTER TABLE banner
+    DROP COLUMN tag_ids;
+-- +goose StatementEnd
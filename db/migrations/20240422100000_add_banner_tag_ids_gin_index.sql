@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- speeds up lookup of banners containing a tag: tag_ids @> ARRAY[tag_id]
+CREATE INDEX banner_tag_ids_gin_idx ON banner USING gin (tag_ids);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX banner_tag_ids_gin_idx;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- notifies listeners about feature and tags of the banner before and after each change,
+-- so every instance can evict affected cache entries. Trigger covers cascade deletions too
+CREATE FUNCTION notify_banner_change() RETURNS trigger AS
+$$
+BEGIN
+    PERFORM pg_notify('banner_changes', json_build_object(
+            'banner_id', CASE WHEN TG_OP = 'DELETE' THEN OLD.id ELSE NEW.id END,
+            'old', CASE
+                       WHEN TG_OP = 'INSERT' THEN NULL
+                       ELSE json_build_object('feature_id', OLD.feature_id, 'tag_ids', OLD.tag_ids) END,
+            'new', CASE
+                       WHEN TG_OP = 'DELETE' THEN NULL
+                       ELSE json_build_object('feature_id', NEW.feature_id, 'tag_ids', NEW.tag_ids) END
+        )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER banner_change_notify
+    AFTER INSERT OR UPDATE OR DELETE
+    ON banner
+    FOR EACH ROW
+EXECUTE FUNCTION notify_banner_change();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER banner_change_notify ON banner;
+
+DROP FUNCTION notify_banner_change();
+-- +goose StatementEnd
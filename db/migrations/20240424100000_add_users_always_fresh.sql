@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- users with always_fresh get banners from the database bypassing caches, as if they passed use_last_revision
+ALTER TABLE users
+    ADD COLUMN always_fresh boolean not null default false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN always_fresh;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+-- priority picks one of several banners matching user tags, default banner is shown when none matches
+ALTER TABLE banner
+    ADD COLUMN priority   integer not null default 0,
+    ADD COLUMN is_default boolean not null default false;
+
+CREATE UNIQUE INDEX banner_feature_id_default_key ON banner (feature_id) WHERE is_default;
+
+-- change of default banner affects lookups with any tags of the feature, so listeners need to know about it
+CREATE OR REPLACE FUNCTION notify_banner_change() RETURNS trigger AS
+$$
+BEGIN
+    PERFORM pg_notify('banner_changes', json_build_object(
+            'banner_id', CASE WHEN TG_OP = 'DELETE' THEN OLD.id ELSE NEW.id END,
+            'old', CASE
+                       WHEN TG_OP = 'INSERT' THEN NULL
+                       ELSE json_build_object('feature_id', OLD.feature_id, 'tag_ids', OLD.tag_ids,
+                                              'is_default', OLD.is_default) END,
+            'new', CASE
+                       WHEN TG_OP = 'DELETE' THEN NULL
+                       ELSE json_build_object('feature_id', NEW.feature_id, 'tag_ids', NEW.tag_ids,
+                                              'is_default', NEW.is_default) END
+        )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_banner_change() RETURNS trigger AS
+$$
+BEGIN
+    PERFORM pg_notify('banner_changes', json_build_object(
+            'banner_id', CASE WHEN TG_OP = 'DELETE' THEN OLD.id ELSE NEW.id END,
+            'old', CASE
+                       WHEN TG_OP = 'INSERT' THEN NULL
+                       ELSE json_build_object('feature_id', OLD.feature_id, 'tag_ids', OLD.tag_ids) END,
+            'new', CASE
+                       WHEN TG_OP = 'DELETE' THEN NULL
+                       ELSE json_build_object('feature_id', NEW.feature_id, 'tag_ids', NEW.tag_ids) END
+        )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX banner_feature_id_default_key;
+
+ALTER TABLE banner
+    DROP COLUMN priority,
+    DROP COLUMN is_default;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- variants are alternative contents of the banner: [{"key": "a", "weight": 1, "content": {...}}, ...]
+ALTER TABLE banner
+    ADD COLUMN variants jsonb;
+
+ALTER TABLE banner_version
+    ADD COLUMN variants jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE banner_version
+    DROP COLUMN variants;
+
+ALTER TABLE banner
+    DROP COLUMN variants;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- rollout_previous is content shown to users outside of rollout: {"content": {...}, "variants": [...]}
+ALTER TABLE banner
+    ADD COLUMN rollout_percent  smallint NOT NULL DEFAULT 100 CHECK (rollout_percent BETWEEN 0 AND 100),
+    ADD COLUMN rollout_previous jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE banner
+    DROP COLUMN rollout_previous,
+    DROP COLUMN rollout_percent;
+-- +goose StatementEnd
@@ -0,0 +1,71 @@
+-- +goose Up
+-- +goose StatementBegin
+-- id of the transaction that changed the banner last. Transactions with ids below xmin of a snapshot are all
+-- finished, so index reads changes incrementally in commit order remembering xmin of the snapshot of previous read
+ALTER TABLE banner
+    ADD COLUMN change_xid xid8 not null default pg_current_xact_id();
+
+CREATE INDEX banner_change_xid_idx ON banner (change_xid);
+
+CREATE FUNCTION set_banner_change_xid() RETURNS trigger AS
+$$
+BEGIN
+    NEW.change_xid = pg_current_xact_id();
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER banner_change_xid
+    BEFORE UPDATE
+    ON banner
+    FOR EACH ROW
+EXECUTE FUNCTION set_banner_change_xid();
+
+-- ids of deleted banners read by index the same way as changes, they are kept for a day:
+-- index not refreshed for longer reloads all banners
+CREATE TABLE banner_tombstone
+(
+    banner_id  bigint      not null primary key,
+    change_xid xid8        not null default pg_current_xact_id(),
+    deleted_at timestamptz not null default now()
+);
+
+CREATE INDEX banner_tombstone_change_xid_idx ON banner_tombstone (change_xid);
+
+CREATE INDEX banner_tombstone_deleted_at_idx ON banner_tombstone (deleted_at);
+
+-- trigger covers cascade deletions too
+CREATE FUNCTION add_banner_tombstone() RETURNS trigger AS
+$$
+BEGIN
+    INSERT INTO banner_tombstone (banner_id) VALUES (OLD.id);
+
+    DELETE FROM banner_tombstone WHERE deleted_at < now() - interval '1 day';
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER banner_tombstone
+    AFTER DELETE
+    ON banner
+    FOR EACH ROW
+EXECUTE FUNCTION add_banner_tombstone();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER banner_tombstone ON banner;
+
+DROP FUNCTION add_banner_tombstone();
+
+DROP TABLE banner_tombstone;
+
+DROP TRIGGER banner_change_xid ON banner;
+
+DROP FUNCTION set_banner_change_xid();
+
+ALTER TABLE banner
+    DROP COLUMN change_xid;
+-- +goose StatementEnd
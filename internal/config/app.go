@@ -5,4 +5,7 @@ type Config struct {
 	Jwt
 	Postgres
 	Cache
+	Jobs
+	BannerIndex    `mapstructure:"banner_index"`
+	BannerMatching `mapstructure:"banner_matching"`
 }
@@ -0,0 +1,8 @@
+package config
+
+type BannerIndex struct {
+	// RefreshInterval is an interval in seconds between refreshes of in-memory banner index
+	RefreshInterval int `mapstructure:"refresh_interval"`
+	// MaxStaleness is a time in seconds since the last successful refresh after which index isn't used
+	MaxStaleness int `mapstructure:"max_staleness"`
+}
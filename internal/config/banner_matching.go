@@ -0,0 +1,6 @@
+package config
+
+type BannerMatching struct {
+	// Mode is one of exact (default), best_subset or any_tag, see entity.BannerMatching
+	Mode string `mapstructure:"mode"`
+}
@@ -1,6 +1,39 @@
 package config
 
+const (
+	CacheBackendInMem = "inmem"
+	CacheBackendLRU   = "lru"
+	CacheBackendRedis = "redis"
+)
+
 type Cache struct {
-	Expiration      int `yaml:"expiration"`
-	CleanupInterval int `yaml:"cleanup_interval"`
+	// Backend is one of inmem (default), lru or redis
+	Backend string `mapstructure:"backend"`
+	// Expiration is a default lifetime of cached entries in minutes
+	Expiration int `mapstructure:"expiration"`
+	// StaleWhileRevalidate is a time in minutes expired banners are still served while being refreshed in background
+	StaleWhileRevalidate int `mapstructure:"stale_while_revalidate"`
+	// StaleIfError is a time in minutes expired banners are still served while database is unavailable
+	StaleIfError int `mapstructure:"stale_if_error"`
+	// ClientMaxAge is a time in seconds clients may keep user banner response fresh, 0 makes them revalidate it
+	ClientMaxAge int `mapstructure:"client_max_age"`
+	// ClientStaleWhileRevalidate is a time in seconds after ClientMaxAge clients may serve response while revalidating it
+	ClientStaleWhileRevalidate int `mapstructure:"client_stale_while_revalidate"`
+	// NegativeExpiration is a lifetime in seconds of cached absence of banner, 0 disables caching of missing banners
+	NegativeExpiration int `mapstructure:"negative_expiration"`
+	// FetchTimeout is a max time in seconds of database query filling the cache, shared by concurrent requests
+	FetchTimeout int `mapstructure:"fetch_timeout"`
+	// CleanupInterval is an interval in minutes between removals of expired entries of inmem backend
+	CleanupInterval int `mapstructure:"cleanup_interval"`
+	// Size is a max number of entries of lru backend
+	Size  int        `mapstructure:"size"`
+	Redis CacheRedis `mapstructure:"redis"`
+}
+
+type CacheRedis struct {
+	Addr     string `mapstructure:"addr"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+	// KeyPrefix is prepended to all keys, so cache can share database with other data
+	KeyPrefix string `mapstructure:"key_prefix"`
 }
@@ -0,0 +1,9 @@
+package config
+
+type Jobs struct {
+	// PollInterval is an interval in seconds between checks for pending jobs
+	PollInterval int `mapstructure:"poll_interval"`
+	// LeaseTimeout is a time in seconds after which running job not extended by its instance is run again,
+	// zero disables reclaiming
+	LeaseTimeout int `mapstructure:"lease_timeout"`
+}
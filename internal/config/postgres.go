@@ -11,6 +11,11 @@ type Postgres struct {
 
 	Retries  int
 	Interval int
+
+	// BreakerFailures is a number of connectivity errors in a row after which banner lookups stop going to database
+	BreakerFailures int `mapstructure:"breaker_failures"`
+	// BreakerTimeout is a time in seconds after which lookups are tried again
+	BreakerTimeout int `mapstructure:"breaker_timeout"`
 }
 
 func (p *Postgres) ConnectionDSN() string {
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+type BannerStatus = string
+
+const (
+	// BannerStatusScheduled is a status of banner which activation window is not started yet
+	BannerStatusScheduled BannerStatus = "scheduled"
+	// BannerStatusLive is a status of turned on banner within its activation window
+	BannerStatusLive BannerStatus = "live"
+	// BannerStatusExpired is a status of banner which activation window is over
+	BannerStatusExpired BannerStatus = "expired"
+)
+
+// Activity describes when banner is shown to users: it must be turned on and current time must be within
+// optional activation window [ActiveFrom, ActiveUntil)
+type Activity struct {
+	IsActive    bool       `db:"is_active"`
+	ActiveFrom  *time.Time `db:"active_from"`
+	ActiveUntil *time.Time `db:"active_until"`
+}
+
+func (a Activity) IsActiveAt(t time.Time) bool {
+	if !a.IsActive {
+		return false
+	}
+
+	if a.ActiveFrom != nil && t.Before(*a.ActiveFrom) {
+		return false
+	}
+
+	if a.ActiveUntil != nil && !t.Before(*a.ActiveUntil) {
+		return false
+	}
+
+	return true
+}
@@ -7,7 +7,18 @@ type Banner struct {
 	TagIDs    []int `db:"tag_ids"`
 	FeatureID int   `db:"feature_id"`
 	Content
-	IsActive  bool      `db:"is_active"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	// Variants are alternative contents shown instead of Content, each user always gets the same one, see VariantFor
+	Variants []BannerVariant `db:"-"`
+	Activity
+	// Priority decides which of banners matching user tags is shown, higher wins
+	Priority int `db:"priority"`
+	// IsDefault marks banner shown for its feature when no banner matches user tags, feature has at most one
+	IsDefault bool `db:"is_default"`
+	// Rollout limits share of users who see the banner, nil or full percent means all users see it, see ForUser
+	Rollout   *BannerRollout `db:"-"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+
+	// UpdatedBy is an id of the user who made the change, it's saved only to banner version
+	UpdatedBy int `db:"-"`
 }
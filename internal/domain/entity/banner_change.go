@@ -0,0 +1,26 @@
+package entity
+
+// BannerKey is a feature and a set of tags identifying the banner
+type BannerKey struct {
+	FeatureID int
+	TagIDs    []int
+	// IsDefault reports that banner is the default one of the feature, its change affects all tags of the feature
+	IsDefault bool
+}
+
+// BannerChangeSet is banners changed and ids of banners deleted since the cursor along with the cursor to read
+// following changes from
+type BannerChangeSet struct {
+	Changed    []*Banner
+	DeletedIDs []int
+	Cursor     uint64
+}
+
+// BannerChange is a notification about created, updated or deleted banner
+type BannerChange struct {
+	BannerID int
+	// Old is a key of the banner before the change, nil if banner is created
+	Old *BannerKey
+	// New is a key of the banner after the change, nil if banner is deleted
+	New *BannerKey
+}
@@ -0,0 +1,13 @@
+package entity
+
+// BannerMatching is a way user tags are matched against tags of banners
+type BannerMatching = string
+
+const (
+	// BannerMatchingExact matches banner with exactly the same set of tags
+	BannerMatchingExact BannerMatching = "exact"
+	// BannerMatchingBestSubset matches active banner with tags all of which user has, the one with most tags wins
+	BannerMatchingBestSubset BannerMatching = "best_subset"
+	// BannerMatchingAnyTag matches active banner with any of user tags, the one with highest priority wins
+	BannerMatchingAnyTag BannerMatching = "any_tag"
+)
@@ -0,0 +1,40 @@
+package entity
+
+import "encoding/json"
+
+// FullRollout is rollout percent of banners shown to all users
+const FullRollout = 100
+
+// BannerRollout limits share of users who see the banner, the rest see previously active content of the banner
+type BannerRollout struct {
+	// Percent of users who see the banner, in [0, 100]
+	Percent int `json:"percent"`
+	// Previous is the last content shown to all users, users outside of rollout see it. It's kept with full percent
+	// too, so rollout started after content was replaced still shows it. Nil means they don't see the banner at all
+	Previous *BannerSnapshot `json:"previous,omitempty"`
+}
+
+// BannerSnapshot is content of the banner along with its variants saved when the banner is replaced
+type BannerSnapshot struct {
+	Content  json.RawMessage `json:"content"`
+	Variants []BannerVariant `json:"variants,omitempty"`
+}
+
+// ForUser returns banner as it's shown to the user: the banner itself if user is in rollout, the banner with previous
+// content if not, or nil if user is out of rollout and there is no previous content. The same user stays in rollout
+// while it grows, because user bucket doesn't depend on the percent
+func (b *Banner) ForUser(userID int) *Banner {
+	if b.Rollout == nil || int(userBucket(b.ID, userID, "rollout", FullRollout)) < b.Rollout.Percent {
+		return b
+	}
+
+	if b.Rollout.Previous == nil {
+		return nil
+	}
+
+	previous := *b
+	previous.Content.Data = b.Rollout.Previous.Content
+	previous.Variants = b.Rollout.Previous.Variants
+
+	return &previous
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BannerUpdate is a partial change of the banner, zero, nil and empty fields are kept unless stated otherwise
+type BannerUpdate struct {
+	TagIDs    []int
+	FeatureID int
+	Content   json.RawMessage
+	// Variants are kept if nil, empty ones are removed
+	Variants []BannerVariant
+	IsActive *bool
+	// ActiveFrom and ActiveUntil are kept if nil, bound with nil time is removed
+	ActiveFrom  *TimeBound
+	ActiveUntil *TimeBound
+	Priority    *int
+	IsDefault   *bool
+	// Rollout is kept if nil
+	Rollout *BannerRollout
+
+	// UpdatedBy is an id of the user who made the change
+	UpdatedBy int
+}
+
+// TimeBound is a new value of optional bound of activation window, nil At removes the bound
+type TimeBound struct {
+	At *time.Time
+}
+
+// Apply returns banner with the update applied
+func (u BannerUpdate) Apply(banner Banner) Banner {
+	if u.FeatureID != 0 {
+		banner.FeatureID = u.FeatureID
+	}
+
+	if len(u.TagIDs) != 0 {
+		banner.TagIDs = u.TagIDs
+	}
+
+	if len(u.Content) != 0 {
+		banner.Content.Data = u.Content
+	}
+
+	if u.Variants != nil {
+		banner.Variants = u.Variants
+	}
+
+	if u.IsActive != nil {
+		banner.IsActive = *u.IsActive
+	}
+
+	if u.ActiveFrom != nil {
+		banner.ActiveFrom = u.ActiveFrom.At
+	}
+
+	if u.ActiveUntil != nil {
+		banner.ActiveUntil = u.ActiveUntil.At
+	}
+
+	if u.Priority != nil {
+		banner.Priority = *u.Priority
+	}
+
+	if u.IsDefault != nil {
+		banner.IsDefault = *u.IsDefault
+	}
+
+	// full rollout without previous content means no rollout
+	if u.Rollout != nil {
+		banner.Rollout = u.Rollout
+
+		if u.Rollout.Percent >= FullRollout && u.Rollout.Previous == nil {
+			banner.Rollout = nil
+		}
+	}
+
+	return banner
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+)
+
+// BannerVariant is one of alternative contents of the banner shown to a share of users proportional to its weight
+type BannerVariant struct {
+	Key     string          `json:"key"`
+	Weight  int             `json:"weight"`
+	Content json.RawMessage `json:"content"`
+}
+
+// userBucket returns stable number in [0, n) assigned to the user for the banner, salt separates independent
+// assignments of the same user to the same banner
+func userBucket(bannerID, userID int, salt string, n uint32) uint32 {
+	hash := fnv.New32a()
+	_, _ = fmt.Fprintf(hash, "%d:%d:%s", bannerID, userID, salt)
+
+	return hash.Sum32() % n
+}
+
+// VariantFor returns content variant of the banner shown to the user, the same user always gets the same variant
+// while variants don't change. Nil means banner has no variants and its content is shown
+func (b *Banner) VariantFor(userID int) *BannerVariant {
+	total := 0
+	for _, variant := range b.Variants {
+		total += variant.Weight
+	}
+
+	if total <= 0 {
+		return nil
+	}
+
+	point := int(userBucket(b.ID, userID, "variant", uint32(total)))
+
+	for i := range b.Variants {
+		point -= b.Variants[i].Weight
+		if point < 0 {
+			return &b.Variants[i]
+		}
+	}
+
+	return nil
+}
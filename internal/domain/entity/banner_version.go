@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+type BannerVersion struct {
+	ID        int   `db:"id"`
+	BannerID  int   `db:"banner_id"`
+	Version   int   `db:"version"`
+	TagIDs    []int `db:"tag_ids"`
+	FeatureID int   `db:"feature_id"`
+	Content
+	Variants []BannerVariant `db:"-"`
+	Activity
+	Priority  int            `db:"priority"`
+	IsDefault bool           `db:"is_default"`
+	Rollout   *BannerRollout `db:"-"`
+	CreatedBy int            `db:"created_by"`
+	CreatedAt time.Time      `db:"created_at"`
+}
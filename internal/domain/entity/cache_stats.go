@@ -0,0 +1,19 @@
+package entity
+
+// CacheStats are counters of user banner lookups by the way they were served since the start of the instance
+type CacheStats struct {
+	// IndexHits are lookups served by in-memory index
+	IndexHits int64
+	// Hits are lookups served by fresh cache entry
+	Hits int64
+	// StaleHits are lookups served by expired cache entry while it's refreshed in background
+	StaleHits int64
+	// NegativeHits are lookups of missing banners answered by cache without database
+	NegativeHits int64
+	// Misses are lookups that went to database because cache had no entry
+	Misses int64
+	// Bypasses are lookups that went to database because last revision was requested
+	Bypasses int64
+	// StaleIfErrorHits are lookups served by expired cache entry because database was unavailable
+	StaleIfErrorHits int64
+}
@@ -1,8 +1,9 @@
 package entity
 
+import "encoding/json"
+
+// Content is a JSON object of undefined structure
 type Content struct {
-	ID    int    `db:"content_id"`
-	Title string `db:"title"`
-	Text  string `db:"text"`
-	Url   string `db:"url"`
+	ID   int             `db:"content_id"`
+	Data json.RawMessage `db:"data"`
 }
@@ -1,10 +1,15 @@
 package entity
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Feature struct {
-	ID        int       `db:"id"`
-	Name      string    `db:"name"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+	// ContentSchema is a JSON schema content of feature's banners must match
+	ContentSchema json.RawMessage `db:"content_schema"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
 }
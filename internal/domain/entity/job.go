@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+type JobStatus = string
+
+const (
+	JobStatusPending JobStatus = "pending"
+	JobStatusRunning JobStatus = "running"
+	JobStatusDone    JobStatus = "done"
+	JobStatusFailed  JobStatus = "failed"
+)
+
+// Job is a deferred deletion of banners by feature and/or tag, zero FeatureID or TagID means any
+type Job struct {
+	ID        int       `db:"id"`
+	FeatureID int       `db:"feature_id"`
+	TagID     int       `db:"tag_id"`
+	Status    JobStatus `db:"status"`
+	Deleted   int       `db:"deleted"`
+	Error     string    `db:"error"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
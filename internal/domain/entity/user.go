@@ -6,6 +6,7 @@ type User struct {
 	ID             int       `db:"id"`
 	Username       string    `db:"username"`
 	IsAdmin        bool      `db:"is_admin"`
+	AlwaysFresh    bool      `db:"always_fresh"`
 	HashedPassword string    `db:"hashed_password"`
 	CreatedAt      time.Time `db:"created_at"`
 	UpdatedAt      time.Time `db:"updated_at"`
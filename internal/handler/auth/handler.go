@@ -73,8 +73,9 @@ func (h *Handler) Routes() *chi.Mux {
 //	@Produce		json
 //	@Param			input	body		request.RegisterRequest	true	"register user schema"
 //	@Success		200		{object}	response.RegisterUserResponse
-//	@Failure		400		{string}	invalid		request
-//	@Failure		500		{string}	internal	error
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		409		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
 //	@Router			/avito-trainee/api/v1/auth/user_register [post]
 func (h *Handler) RegisterUser(rw http.ResponseWriter, req *http.Request) {
 	var registerReq request.RegisterRequest
@@ -82,7 +83,7 @@ func (h *Handler) RegisterUser(rw http.ResponseWriter, req *http.Request) {
 	if err := render.DecodeJSON(req.Body, &registerReq); err != nil {
 		msg := fmt.Sprintf("error occurred decoding request body to RegisterRequest srtuct: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "request body is not a valid JSON")
 
 		return
 	}
@@ -90,7 +91,7 @@ func (h *Handler) RegisterUser(rw http.ResponseWriter, req *http.Request) {
 	if err := registerReq.Validate(h.validator); err != nil {
 		msg := fmt.Sprintf("error occurred validating RegisterRequest struct: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
 
 		return
 	}
@@ -99,7 +100,7 @@ func (h *Handler) RegisterUser(rw http.ResponseWriter, req *http.Request) {
 	if err != nil {
 		msg := fmt.Sprintf("error occurred registering user: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
 
 		return
 	}
@@ -118,10 +119,11 @@ func (h *Handler) RegisterUser(rw http.ResponseWriter, req *http.Request) {
 //	@Produce		json
 //	@Param			input	body		request.RegisterRequest	true	"register user schema"
 //	@Success		200		{object}	response.RegisterUserResponse
-//	@Failure		401		{string}	Unauthorized
-//	@Failure		403		{string}	Forbidden
-//	@Failure		400		{string}	invalid		request
-//	@Failure		500		{string}	internal	error
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		409		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
 //	@Router			/avito-trainee/api/v1/auth/admin_register [post]
 func (h *Handler) RegisterAdmin(rw http.ResponseWriter, req *http.Request) {
 	var registerReq request.RegisterRequest
@@ -129,7 +131,7 @@ func (h *Handler) RegisterAdmin(rw http.ResponseWriter, req *http.Request) {
 	if err := render.DecodeJSON(req.Body, &registerReq); err != nil {
 		msg := fmt.Sprintf("error occurred decoding request body to RegisterRequest srtuct: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "request body is not a valid JSON")
 
 		return
 	}
@@ -137,7 +139,7 @@ func (h *Handler) RegisterAdmin(rw http.ResponseWriter, req *http.Request) {
 	if err := registerReq.Validate(h.validator); err != nil {
 		msg := fmt.Sprintf("error occurred validating RegisterRequest struct: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
 
 		return
 	}
@@ -146,7 +148,7 @@ func (h *Handler) RegisterAdmin(rw http.ResponseWriter, req *http.Request) {
 	if err != nil {
 		msg := fmt.Sprintf("error occurred registering admin: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
 
 		return
 	}
@@ -164,8 +166,9 @@ func (h *Handler) RegisterAdmin(rw http.ResponseWriter, req *http.Request) {
 //	@Produce		plain
 //	@Param			input	body		request.LoginRequest	true	"login info"
 //	@Success		200		{object}	response.LoginResponse
-//	@Failure		400		{string}	invalid		login	data	provided
-//	@Failure		500		{string}	internal	error
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
 //	@Router			/avito-trainee/api/v1/auth/login [post]
 func (h *Handler) Login(rw http.ResponseWriter, req *http.Request) {
 	var loginReq request.LoginRequest
@@ -173,7 +176,7 @@ func (h *Handler) Login(rw http.ResponseWriter, req *http.Request) {
 	if err := render.DecodeJSON(req.Body, &loginReq); err != nil {
 		msg := fmt.Sprintf("error occurred decoding request body to LoginRequest struct: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "request body is not a valid JSON")
 
 		return
 	}
@@ -181,7 +184,7 @@ func (h *Handler) Login(rw http.ResponseWriter, req *http.Request) {
 	if err := loginReq.Validate(h.validator); err != nil {
 		msg := fmt.Sprintf("error occurred validating LoginRequest struct: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
 
 		return
 	}
@@ -190,23 +193,24 @@ func (h *Handler) Login(rw http.ResponseWriter, req *http.Request) {
 	if err != nil {
 		msg := fmt.Sprintf("error occurred while user login: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
 
 		return
 	}
 
 	// construct jwt token
 	payload := map[string]any{
-		"id":       user.ID,
-		"username": user.Username,
-		"is_admin": user.IsAdmin,
+		"id":           user.ID,
+		"username":     user.Username,
+		"is_admin":     user.IsAdmin,
+		"always_fresh": user.AlwaysFresh,
 	}
 
 	token, err := jwtutils.CreateJWT(payload, jwt.SigningMethodHS256, h.jwtConfig.Secret)
 	if err != nil {
 		msg := fmt.Sprintf("error occurred signing jwt token: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusInternalServerError, msg, msg)
 
 		return
 	}
@@ -21,27 +21,42 @@ import (
 )
 
 type Service interface {
-	GetAllBanners(ctx context.Context, offset, limit int) ([]*entity.Banner, error)
-	GetBannersWithFeatureAndTag(ctx context.Context, featureID, tagID int, offset, limit int) ([]*entity.Banner, error)
-	GetBannerByFeatureAndTags(ctx context.Context, featureID int, tagIDs []int) (*entity.Banner, error)
+	GetAllBanners(ctx context.Context, status entity.BannerStatus, offset, limit int) ([]*entity.Banner, error)
+	GetBannersWithFeatureAndTag(ctx context.Context, featureID, tagID int, status entity.BannerStatus, offset, limit int) ([]*entity.Banner, error)
+	GetBannerByFeatureAndTags(ctx context.Context, featureID int, tagIDs []int, useLastRevision bool) (*entity.Banner, error)
 	CreateBanner(ctx context.Context, banner entity.Banner) (*entity.Banner, error)
-	UpdateBanner(ctx context.Context, id int, updateModel entity.Banner) error
+	UpdateBanner(ctx context.Context, id int, update entity.BannerUpdate) error
 	DeleteBanner(ctx context.Context, id int) (*entity.Banner, error)
+	GetBannerVersions(ctx context.Context, id int, offset, limit int) ([]*entity.BannerVersion, error)
+	RestoreBannerVersion(ctx context.Context, id, version, restoredBy int) error
+	CacheStats() entity.CacheStats
+}
+
+type JobService interface {
+	CreateDeleteBannersJob(ctx context.Context, featureID, tagID int) (*entity.Job, error)
 }
 
 type Middleware = func(http.Handler) http.Handler
 
 type Handler struct {
 	Service     Service
+	JobService  JobService
 	Middlewares []Middleware
 
 	logger    *logrus.Logger
 	validator *validator.Validate
 }
 
-func New(service Service, logger *logrus.Logger, validator *validator.Validate, middlewares ...Middleware) *Handler {
+func New(
+	service Service,
+	jobService JobService,
+	logger *logrus.Logger,
+	validator *validator.Validate,
+	middlewares ...Middleware,
+) *Handler {
 	return &Handler{
 		Service:     service,
+		JobService:  jobService,
 		Middlewares: middlewares,
 		logger:      logger,
 		validator:   validator,
@@ -55,10 +70,14 @@ func (h *Handler) Routes() *chi.Mux {
 		r.Use(h.Middlewares...)
 
 		r.Get("/all", h.GetAllBanners)
+		r.Get("/cache_stats", h.GetCacheStats)
 		r.Get("/", h.GetBannersWithFeatureAndTag)
 		r.Post("/", h.CreateBanner)
 		r.Patch("/{id}", h.UpdateBanner)
+		r.Delete("/", h.DeleteBanners)
 		r.Delete("/{id}", h.DeleteBanner)
+		r.Get("/{id}/versions", h.GetBannerVersions)
+		r.Post("/{id}/versions/{version}/restore", h.RestoreBannerVersion)
 	})
 
 	return router
@@ -73,13 +92,14 @@ func (h *Handler) Routes() *chi.Mux {
 //	@Accept			json
 //	@Produce		json
 //	@Param token 	header string true "admin auth token"
+//	@Param			status	query		string	false	"Status filter"	Enums(scheduled, live, expired)
 //	@Param			offset	query		int	true	"Offset"
 //	@Param			limit	query		int	true	"Limit"
 //	@Success		200		{object}	[]response.GetAdminBannerResponse
-//	@Failure		401		{string}	Unauthorized
-//	@Failure		403		{string}	Forbidden
-//	@Failure		400		{string}	invalid		request
-//	@Failure		500		{string}	internal	error
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
 //	@Router			/avito-trainee/api/v1/banner/all [get]
 func (h *Handler) GetAllBanners(rw http.ResponseWriter, req *http.Request) {
 	paginationOpts := handlerinternalutils.GetPaginationOptsFromQuery(req, DefaultOffset, DefaultLimit)
@@ -87,16 +107,25 @@ func (h *Handler) GetAllBanners(rw http.ResponseWriter, req *http.Request) {
 	if err := paginationOpts.Validate(h.validator); err != nil {
 		msg := fmt.Sprintf("invalid pagination options provided: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
 
 		return
 	}
 
-	banners, err := h.Service.GetAllBanners(req.Context(), paginationOpts.Offset, paginationOpts.Limit)
+	status, err := handlerinternalutils.GetBannerStatusFromQuery(req)
+	if err != nil {
+		msg := fmt.Sprintf("invalid status query param provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, err.Error())
+
+		return
+	}
+
+	banners, err := h.Service.GetAllBanners(req.Context(), status, paginationOpts.Offset, paginationOpts.Limit)
 	if err != nil {
 		msg := fmt.Sprintf("error occurred fetching banners: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
 
 		return
 	}
@@ -105,6 +134,23 @@ func (h *Handler) GetAllBanners(rw http.ResponseWriter, req *http.Request) {
 	rw.WriteHeader(http.StatusOK)
 }
 
+// GetCacheStats godoc
+//
+//	@Summary		Get user banner cache stats
+//	@Description	Get counters of user banner lookups by the way they were served since the start of the instance
+//	@Security		JWT
+//	@Tags			Banner
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Success		200		{object}	response.GetCacheStatsResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/banner/cache_stats [get]
+func (h *Handler) GetCacheStats(rw http.ResponseWriter, req *http.Request) {
+	render.JSON(rw, req, mapper.MapCacheStatsToGetCacheStatsResponse(h.Service.CacheStats()))
+	rw.WriteHeader(http.StatusOK)
+}
+
 // GetBannersWithFeatureAndTag godoc
 //
 //	@Summary		Get banners have feature and tag
@@ -116,20 +162,22 @@ func (h *Handler) GetAllBanners(rw http.ResponseWriter, req *http.Request) {
 //	@Param token 	header string true "admin auth token"
 //	@Param			feature_id	query		int	true	"Feature ID"
 //	@Param			tag_id	query		int	true	"Tag ID"
+//	@Param			status	query		string	false	"Status filter"	Enums(scheduled, live, expired)
 //	@Param			offset	query		int	true	"Offset"
 //	@Param			limit	query		int	true	"Limit"
 //	@Success		200		{object}	[]response.GetAdminBannerResponse
-//	@Failure		401		{string}	Unauthorized
-//	@Failure		403		{string}	Forbidden
-//	@Failure		400		{string}	invalid		request
-//	@Failure		500		{string}	internal	error
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		404		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
 //	@Router			/avito-trainee/api/v1/banner [get]
 func (h *Handler) GetBannersWithFeatureAndTag(rw http.ResponseWriter, req *http.Request) {
 	featureID, err := handlerutils.GetIntParamFromQuery(req, "feature_id")
 	if err != nil {
 		msg := fmt.Sprintf("invalid feature_id query param provided: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "feature_id must be a positive integer")
 
 		return
 	}
@@ -138,7 +186,7 @@ func (h *Handler) GetBannersWithFeatureAndTag(rw http.ResponseWriter, req *http.
 	if err != nil {
 		msg := fmt.Sprintf("invalid tag_id query param provided: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "tag_id must be a positive integer")
 
 		return
 	}
@@ -148,16 +196,32 @@ func (h *Handler) GetBannersWithFeatureAndTag(rw http.ResponseWriter, req *http.
 	if err = paginationOpts.Validate(h.validator); err != nil {
 		msg := fmt.Sprintf("invalid pagination options provided: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
 
 		return
 	}
 
-	banners, err := h.Service.GetBannersWithFeatureAndTag(req.Context(), featureID, tagID, paginationOpts.Offset, paginationOpts.Limit)
+	status, err := handlerinternalutils.GetBannerStatusFromQuery(req)
+	if err != nil {
+		msg := fmt.Sprintf("invalid status query param provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, err.Error())
+
+		return
+	}
+
+	banners, err := h.Service.GetBannersWithFeatureAndTag(
+		req.Context(),
+		featureID,
+		tagID,
+		status,
+		paginationOpts.Offset,
+		paginationOpts.Limit,
+	)
 	if err != nil {
 		msg := fmt.Sprintf("error occurred fetching banners: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
 
 		return
 	}
@@ -177,10 +241,11 @@ func (h *Handler) GetBannersWithFeatureAndTag(rw http.ResponseWriter, req *http.
 //	@Param token 	header string true "admin auth token"
 //	@Param			input	body		request.CreateBannerRequest	true	"create banner schema"
 //	@Success		200		{object}	response.CreateBannerResponse
-//	@Failure		401		{string}	Unauthorized
-//	@Failure		403		{string}	Forbidden
-//	@Failure		400		{string}	invalid		request
-//	@Failure		500		{string}	internal	error
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		409		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
 //	@Router			/avito-trainee/api/v1/banner [post]
 func (h *Handler) CreateBanner(rw http.ResponseWriter, req *http.Request) {
 	var bannerReq request.CreateBannerRequest
@@ -188,7 +253,7 @@ func (h *Handler) CreateBanner(rw http.ResponseWriter, req *http.Request) {
 	if err := render.DecodeJSON(req.Body, &bannerReq); err != nil {
 		msg := fmt.Sprintf("error occurred decoding request body to CreateBannerRequest srtuct: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "request body is not a valid JSON")
 
 		return
 	}
@@ -196,16 +261,27 @@ func (h *Handler) CreateBanner(rw http.ResponseWriter, req *http.Request) {
 	if err := bannerReq.Validate(h.validator); err != nil {
 		msg := fmt.Sprintf("error occurred validating CreateBannerRequest struct: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
+
+		return
+	}
+
+	userID, err := handlerutils.GetIntHeaderByKey(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("error occurred getting user id: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusUnauthorized, msg, "invalid token payload")
 
 		return
 	}
 
-	created, err := h.Service.CreateBanner(req.Context(), mapper.MapCreateBannerRequestToEntity(&bannerReq))
+	created, err := h.Service.CreateBanner(req.Context(), mapper.MapCreateBannerRequestToEntity(&bannerReq, userID))
+
 	if err != nil {
 		msg := fmt.Sprintf("error occurred creating banner: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		// content schema violations are reported as field errors
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error(), handlerutils.FieldErrors(err)...)
 
 		return
 	}
@@ -217,7 +293,7 @@ func (h *Handler) CreateBanner(rw http.ResponseWriter, req *http.Request) {
 // UpdateBanner godoc
 //
 //	@Summary		Update existing banner
-//	@Description	Update existing banner
+//	@Description	Update existing banner, omitted fields are kept, null activation window bound removes it
 //	@Security		JWT
 //	@Tags			Banner
 //	@Accept			json
@@ -226,17 +302,19 @@ func (h *Handler) CreateBanner(rw http.ResponseWriter, req *http.Request) {
 //	@Param			input	body	request.UpdateBannerRequest	true	"update banner schema"
 //	@Param			id		path	int							true	"id of the updating banner"
 //	@Success		200
-//	@Failure		401	{string}	Unauthorized
-//	@Failure		403	{string}	Forbidden
-//	@Failure		400	{string}	invalid		request
-//	@Failure		500	{string}	internal	error
+//	@Failure		401	{object}	handler.Problem
+//	@Failure		403	{object}	handler.Problem
+//	@Failure		400	{object}	handler.Problem
+//	@Failure		409	{object}	handler.Problem
+//	@Failure		404	{object}	handler.Problem
+//	@Failure		500	{object}	handler.Problem
 //	@Router			/avito-trainee/api/v1/banner/{id} [patch]
 func (h *Handler) UpdateBanner(rw http.ResponseWriter, req *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(req, "id"))
 	if err != nil {
 		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
 
 		return
 	}
@@ -246,7 +324,7 @@ func (h *Handler) UpdateBanner(rw http.ResponseWriter, req *http.Request) {
 	if err = render.DecodeJSON(req.Body, &updateReq); err != nil {
 		msg := fmt.Sprintf("error occurred decoding request body to UpdateBannerRequest srtuct: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "request body is not a valid JSON")
 
 		return
 	}
@@ -254,15 +332,27 @@ func (h *Handler) UpdateBanner(rw http.ResponseWriter, req *http.Request) {
 	if err = updateReq.Validate(h.validator); err != nil {
 		msg := fmt.Sprintf("error occurred validating UpdateBannerRequest struct: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
 
 		return
 	}
 
-	if err = h.Service.UpdateBanner(req.Context(), id, mapper.MapUpdateBannerRequestToEntity(&updateReq)); err != nil {
+	userID, err := handlerutils.GetIntHeaderByKey(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("error occurred getting user id: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusUnauthorized, msg, "invalid token payload")
+
+		return
+	}
+
+	err = h.Service.UpdateBanner(req.Context(), id, mapper.MapUpdateBannerRequestToEntity(&updateReq, userID))
+
+	if err != nil {
 		msg := fmt.Sprintf("error occurred updating banner: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		// content schema violations are reported as field errors
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error(), handlerutils.FieldErrors(err)...)
 
 		return
 	}
@@ -280,18 +370,19 @@ func (h *Handler) UpdateBanner(rw http.ResponseWriter, req *http.Request) {
 //	@Produce		json
 //	@Param token 	header string true "admin auth token"
 //	@Param			id	path	int	true	"id of the banner"
-//	@Success		200
-//	@Failure		401	{string}	Unauthorized
-//	@Failure		403	{string}	Forbidden
-//	@Failure		400	{string}	invalid		request
-//	@Failure		500	{string}	internal	error
+//	@Success		204
+//	@Failure		401	{object}	handler.Problem
+//	@Failure		403	{object}	handler.Problem
+//	@Failure		400	{object}	handler.Problem
+//	@Failure		404	{object}	handler.Problem
+//	@Failure		500	{object}	handler.Problem
 //	@Router			/avito-trainee/api/v1/banner/{id} [delete]
 func (h *Handler) DeleteBanner(rw http.ResponseWriter, req *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(req, "id"))
 	if err != nil {
 		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
 
 		return
 	}
@@ -300,10 +391,187 @@ func (h *Handler) DeleteBanner(rw http.ResponseWriter, req *http.Request) {
 	if err != nil {
 		msg := fmt.Sprintf("error occurred deleting banner: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// GetBannerVersions godoc
+//
+//	@Summary		Get banner versions
+//	@Description	Get versions of the banner sorting by version descending
+//	@Security		JWT
+//	@Tags			Banner
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			id		path		int	true	"id of the banner"
+//	@Param			offset	query		int	false	"Offset"
+//	@Param			limit	query		int	false	"Limit"
+//	@Success		200		{object}	[]response.GetBannerVersionResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		404		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/banner/{id}/versions [get]
+func (h *Handler) GetBannerVersions(rw http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
+
+		return
+	}
+
+	paginationOpts := handlerinternalutils.GetPaginationOptsFromQuery(req, DefaultOffset, DefaultLimit)
+
+	if err = paginationOpts.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid pagination options provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
+
+		return
+	}
+
+	versions, err := h.Service.GetBannerVersions(req.Context(), id, paginationOpts.Offset, paginationOpts.Limit)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching banner versions: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
 
 		return
 	}
 
+	render.JSON(rw, req, sliceutils.Map(versions, mapper.MapBannerVersionToBannerVersionResponse))
 	rw.WriteHeader(http.StatusOK)
 }
+
+// RestoreBannerVersion godoc
+//
+//	@Summary		Restore banner version
+//	@Description	Make provided version of the banner current, restoring is saved as new version
+//	@Security		JWT
+//	@Tags			Banner
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			id		path	int	true	"id of the banner"
+//	@Param			version	path	int	true	"version of the banner to restore"
+//	@Success		200
+//	@Failure		401	{object}	handler.Problem
+//	@Failure		403	{object}	handler.Problem
+//	@Failure		400	{object}	handler.Problem
+//	@Failure		409	{object}	handler.Problem
+//	@Failure		404	{object}	handler.Problem
+//	@Failure		500	{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/banner/{id}/versions/{version}/restore [post]
+func (h *Handler) RestoreBannerVersion(rw http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
+
+		return
+	}
+
+	version, err := strconv.Atoi(chi.URLParam(req, "version"))
+	if err != nil {
+		msg := fmt.Sprintf("inavlid url param for version provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "version must be an integer")
+
+		return
+	}
+
+	userID, err := handlerutils.GetIntHeaderByKey(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("error occurred getting user id: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusUnauthorized, msg, "invalid token payload")
+
+		return
+	}
+
+	if err = h.Service.RestoreBannerVersion(req.Context(), id, version, userID); err != nil {
+		msg := fmt.Sprintf("error occurred restoring banner version: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error(), handlerutils.FieldErrors(err)...)
+
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+}
+
+// DeleteBanners godoc
+//
+//	@Summary		Delete banners by feature and/or tag
+//	@Description	Create deferred job deleting banners have feature and/or tag, job status is available via /jobs/{id}
+//	@Security		JWT
+//	@Tags			Banner
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			feature_id	query		int	false	"Feature ID"
+//	@Param			tag_id		query		int	false	"Tag ID"
+//	@Success		202			{object}	response.CreateJobResponse
+//	@Failure		401			{object}	handler.Problem
+//	@Failure		403			{object}	handler.Problem
+//	@Failure		400			{object}	handler.Problem
+//	@Failure		500			{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/banner [delete]
+func (h *Handler) DeleteBanners(rw http.ResponseWriter, req *http.Request) {
+	var featureID, tagID int
+
+	if req.URL.Query().Has("feature_id") {
+		id, err := handlerutils.GetIntParamFromQuery(req, "feature_id")
+		if err != nil || id <= 0 {
+			msg := fmt.Sprintf("invalid feature_id query param provided: %v", err)
+
+			handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "feature_id must be a positive integer")
+
+			return
+		}
+
+		featureID = id
+	}
+
+	if req.URL.Query().Has("tag_id") {
+		id, err := handlerutils.GetIntParamFromQuery(req, "tag_id")
+		if err != nil || id <= 0 {
+			msg := fmt.Sprintf("invalid tag_id query param provided: %v", err)
+
+			handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "tag_id must be a positive integer")
+
+			return
+		}
+
+		tagID = id
+	}
+
+	if featureID == 0 && tagID == 0 {
+		msg := "feature_id or tag_id query param must be provided"
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, msg)
+
+		return
+	}
+
+	job, err := h.JobService.CreateDeleteBannersJob(req.Context(), featureID, tagID)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred creating delete banners job: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.Status(req, http.StatusAccepted)
+	render.JSON(rw, req, mapper.MapJobToCreateJobResponse(job))
+}
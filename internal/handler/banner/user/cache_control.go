@@ -0,0 +1,73 @@
+package user
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CacheControl configures caching of user banners by clients and intermediaries
+type CacheControl struct {
+	// MaxAge is a time response is fresh for. It adds up with server side cache TTL and stale window,
+	// so together they must fit the time users may see outdated banners
+	MaxAge time.Duration
+	// StaleWhileRevalidate is a time after MaxAge response may be served while it's revalidated in background
+	StaleWhileRevalidate time.Duration
+	// StaleIfError is a time after MaxAge response may be served if server fails, it's not limited by freshness
+	StaleIfError time.Duration
+	// Vary lists request headers response depends on besides URL, e.g. auth token header. Banner content depends
+	// on the user (visibility of inactive banners, variant and rollout), so shared caches like CDN may store
+	// responses only if they are keyed by the token: without Vary responses are private
+	Vary []string
+}
+
+// header returns Cache-Control header value. Responses to requests for last revision and stale responses
+// must be revalidated every time
+func (c CacheControl) header(revalidate bool) string {
+	visibility := "private"
+	if len(c.Vary) > 0 {
+		visibility = "public"
+	}
+
+	if revalidate || c.MaxAge <= 0 {
+		return visibility + ", no-cache"
+	}
+
+	directives := []string{visibility, fmt.Sprintf("max-age=%d", int(c.MaxAge.Seconds()))}
+
+	if c.StaleWhileRevalidate > 0 {
+		directives = append(directives, fmt.Sprintf("stale-while-revalidate=%d", int(c.StaleWhileRevalidate.Seconds())))
+	}
+
+	if c.StaleIfError > 0 {
+		directives = append(directives, fmt.Sprintf("stale-if-error=%d", int(c.StaleIfError.Seconds())))
+	}
+
+	return strings.Join(directives, ", ")
+}
+
+// contentETag returns strong ETag of content shown to the user, response body is determined by it only
+func contentETag(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports if If-None-Match header value matches etag. Weak comparison is used as RFC 9110 requires
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,10 @@
+package user
+
+const (
+	// StaleHeader is set when database is unavailable and response holds last known banner
+	StaleHeader = "X-Banner-Stale"
+	// VariantHeader holds key of content variant shown to the user if banner has variants
+	VariantHeader = "X-Banner-Variant"
+	// MaxBatchFeatures is a max number of features banners can be requested for at once
+	MaxBatchFeatures = 20
+)
@@ -4,40 +4,50 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 
-	"avito-backend-trainee-2024/internal/domain/entity"
 	"avito-backend-trainee-2024/internal/handler/mapper"
-	"avito-backend-trainee-2024/internal/handler/middleware"
+	"avito-backend-trainee-2024/internal/handler/response"
 
 	handlerutils "avito-backend-trainee-2024/pkg/utils/handler"
-	urlutils "avito-backend-trainee-2024/pkg/utils/url"
+
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
 )
 
 type Service interface {
-	GetBannerByFeatureAndTags(ctx context.Context, featureID int, tagIDs []int) (*entity.Banner, error)
+	LookupBanner(ctx context.Context, featureID int, tagIDs []int, useLastRevision bool) (*bannerservice.BannerLookup, error)
+	LookupBanners(ctx context.Context, featureIDs []int, tagIDs []int, useLastRevision bool) []bannerservice.BatchLookup
 }
 
 type Middleware = func(http.Handler) http.Handler
 
 type Handler struct {
-	Service     Service
-	Middlewares []Middleware
+	Service      Service
+	CacheControl CacheControl
+	Middlewares  []Middleware
 
 	logger    *logrus.Logger
 	validator *validator.Validate
 }
 
-func New(service Service, logger *logrus.Logger, validator *validator.Validate, middlewares ...Middleware) *Handler {
+func New(
+	service Service,
+	cacheControl CacheControl,
+	logger *logrus.Logger,
+	validator *validator.Validate,
+	middlewares ...Middleware,
+) *Handler {
 	return &Handler{
-		Service:     service,
-		Middlewares: middlewares,
-		logger:      logger,
-		validator:   validator,
+		Service:      service,
+		CacheControl: cacheControl,
+		Middlewares:  middlewares,
+		logger:       logger,
+		validator:    validator,
 	}
 }
 
@@ -48,6 +58,7 @@ func (h *Handler) Routes() *chi.Mux {
 		r.Use(h.Middlewares...)
 
 		r.Get("/", h.GetBannerByFeatureAndTags)
+		r.Get("/batch", h.GetBannersByFeaturesAndTags)
 	})
 
 	return router
@@ -64,19 +75,27 @@ func (h *Handler) Routes() *chi.Mux {
 //	@Param token 	header string true "user auth token"
 //	@Param			feature_id	query		string	true	"id of the feature"
 //	@Param			tag_ids		query		[]int	true	"ids of the tags"
-//	@Param			use_last_revision		query		bool	true	"use last revision?"
-//	@Success		200			{object}	response.GetUserBannerResponse
-//	@Failure		401			{string}	Unauthorized
-//	@Failure		400			{string}	invalid		request
-//	@Failure		403			{string}	invalid		request
-//	@Failure		500			{string}	internal	error
+//	@Param			use_last_revision		query		bool	false	"use last revision? Always true for users marked always fresh"
+//	@Param			If-None-Match	header		string	false	"ETag of the banner client already has"
+//	@Success		200			{object}	object	"banner content"
+//	@Success		304			"banner content didn't change"
+//	@Header			200			{string}	X-Banner-Stale	"true if database is unavailable and last known banner is returned"
+//	@Header			200			{string}	X-Banner-Variant	"key of content variant shown to the user, absent if banner has no variants"
+//	@Header			200,304		{string}	ETag	"strong validator of banner content"
+//	@Header			200,304		{string}	Cache-Control	"how long banner may be cached"
+//	@Failure		401			{object}	handler.Problem
+//	@Failure		400			{object}	handler.Problem
+//	@Failure		403			{object}	handler.Problem
+//	@Failure		404		{object}	handler.Problem
+//	@Failure		500			{object}	handler.Problem
+//	@Failure		503			{object}	handler.Problem
 //	@Router			/avito-trainee/api/v1/user_banner [get]
 func (h *Handler) GetBannerByFeatureAndTags(rw http.ResponseWriter, req *http.Request) {
 	featureID, err := handlerutils.GetIntParamFromQuery(req, "feature_id")
 	if err != nil {
 		msg := fmt.Sprintf("error occurred getting 'feature_id' query param: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "feature_id must be an integer")
 
 		return
 	}
@@ -85,36 +104,179 @@ func (h *Handler) GetBannerByFeatureAndTags(rw http.ResponseWriter, req *http.Re
 	if err != nil {
 		msg := fmt.Sprintf("error occurred getting 'tag_ids' query param: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "tag_ids must be a comma separated list of integers")
 
 		return
 	}
 
-	banner, err := h.Service.GetBannerByFeatureAndTags(req.Context(), featureID, tagIDs)
+	// rollout and content variant are picked by id of the user
+	userID, err := handlerutils.GetIntHeaderByKey(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("error occurred getting user id: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusUnauthorized, msg, "invalid token payload")
+
+		return
+	}
+
+	// users marked always fresh get the latest banner without asking for it
+	useLastRevision := req.URL.Query().Get("use_last_revision") == "true" || req.Header.Get("always_fresh") == "true"
+
+	lookup, err := h.Service.LookupBanner(req.Context(), featureID, tagIDs, useLastRevision)
 	if err != nil {
 		msg := fmt.Sprintf("error occurred fetching banner: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
 
 		return
 	}
 
-	// return to users only active banners, if user = admin, then return anyway
-	if !banner.IsActive && req.Header.Get("is_admin") != "true" {
+	banner := lookup.Banner
+
+	// return to users only active banners within activation window, if user = admin, then return anyway
+	if !banner.IsActiveAt(time.Now()) && req.Header.Get("is_admin") != "true" {
 		msg := "banner is inactive"
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusForbidden, msg, msg)
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusForbidden, msg, msg)
+
+		return
+	}
+
+	// users outside of rollout see previous content of the banner or nothing
+	banner = banner.ForUser(userID)
+	if banner == nil {
+		msg := "user is outside of banner rollout"
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusNotFound, msg, bannerservice.ErrNoSuchBanner.Error())
+
+		return
+	}
+
+	// database is unavailable, last known banner is served
+	if lookup.Stale {
+		rw.Header().Set(StaleHeader, "true")
+	}
+
+	variant := banner.VariantFor(userID)
+	if variant != nil {
+		rw.Header().Set(VariantHeader, variant.Key)
+	}
+
+	body := mapper.MapBannerToUserBannerResponse(banner, variant)
+	etag := contentETag(body)
+
+	rw.Header().Set("ETag", etag)
+	rw.Header().Set("Cache-Control", h.CacheControl.header(useLastRevision || lookup.Stale))
+
+	for _, header := range h.CacheControl.Vary {
+		rw.Header().Add("Vary", header)
+	}
+
+	if etagMatches(req.Header.Get("If-None-Match"), etag) {
+		rw.WriteHeader(http.StatusNotModified)
 
 		return
 	}
 
-	resp := mapper.MapBannerToUserBannerResponse(banner)
+	render.JSON(rw, req, body)
+	rw.WriteHeader(http.StatusOK)
+}
+
+// GetBannersByFeaturesAndTags godoc
+//
+//	@Summary		Get banners of several features with tags
+//	@Description	Get banners of several features for the same tags at once. Each banner is looked up the same way
+//	@Description	as by /user_banner, errors are reported per feature
+//	@Security		JWT
+//	@Tags			Banner
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "user auth token"
+//	@Param			feature_ids	query		[]int	true	"ids of the features, at most 20"
+//	@Param			tag_ids		query		[]int	true	"ids of the tags"
+//	@Param			use_last_revision		query		bool	false	"use last revision? Always true for users marked always fresh"
+//	@Success		200			{object}	response.GetUserBannersResponse	"feature id to banner content or error"
+//	@Failure		401			{object}	handler.Problem
+//	@Failure		400			{object}	handler.Problem
+//	@Failure		500			{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/user_banner/batch [get]
+func (h *Handler) GetBannersByFeaturesAndTags(rw http.ResponseWriter, req *http.Request) {
+	featureIDs, err := handlerutils.GetIntArrayParamFromQuery(req, "feature_ids")
+	if err != nil {
+		msg := fmt.Sprintf("error occurred getting 'feature_ids' query param: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "feature_ids must be a comma separated list of integers")
+
+		return
+	}
+
+	if len(featureIDs) > MaxBatchFeatures {
+		msg := fmt.Sprintf("at most %d feature_ids can be provided", MaxBatchFeatures)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, msg)
+
+		return
+	}
+
+	tagIDs, err := handlerutils.GetIntArrayParamFromQuery(req, "tag_ids")
+	if err != nil {
+		msg := fmt.Sprintf("error occurred getting 'tag_ids' query param: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "tag_ids must be a comma separated list of integers")
+
+		return
+	}
+
+	// rollouts and content variants are picked by id of the user
+	userID, err := handlerutils.GetIntHeaderByKey(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("error occurred getting user id: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusUnauthorized, msg, "invalid token payload")
+
+		return
+	}
+
+	// users marked always fresh get the latest banners without asking for it
+	useLastRevision := req.URL.Query().Get("use_last_revision") == "true" || req.Header.Get("always_fresh") == "true"
+	isAdmin := req.Header.Get("is_admin") == "true"
+	now := time.Now()
+
+	resp := make(response.GetUserBannersResponse, len(featureIDs))
+
+	for _, result := range h.Service.LookupBanners(req.Context(), featureIDs, tagIDs, useLastRevision) {
+		if result.Err != nil {
+			status := handlerutils.StatusFromErr(result.Err)
+			detail := result.Err.Error()
+
+			// details of server errors are never sent to the client
+			if status >= http.StatusInternalServerError {
+				h.logger.Errorf("error occurred fetching banner of feature %d: %v", result.FeatureID, result.Err)
+
+				detail = ""
+			}
+
+			resp[result.FeatureID] = mapper.MapErrToUserBannerResult(status, detail)
+
+			continue
+		}
+
+		// return to users only active banners within activation window, if user = admin, then return anyway
+		if !result.Lookup.Banner.IsActiveAt(now) && !isAdmin {
+			resp[result.FeatureID] = mapper.MapErrToUserBannerResult(http.StatusForbidden, "banner is inactive")
+
+			continue
+		}
+
+		// users outside of rollout see previous content of the banner or nothing
+		banner := result.Lookup.Banner.ForUser(userID)
+		if banner == nil {
+			resp[result.FeatureID] = mapper.MapErrToUserBannerResult(http.StatusNotFound, bannerservice.ErrNoSuchBanner.Error())
+
+			continue
+		}
 
-	if middlewareData, ok := req.Context().
-		Value(urlutils.RemoveQueryParamByKey(*req.URL, "use_last_revision").
-			RequestURI()).(middleware.MiddlewareData); ok {
-		middlewareData["banner"] = resp
-		middlewareData["is_active"] = banner.IsActive
+		resp[result.FeatureID] = mapper.MapBannerToUserBannerResult(banner, banner.VariantFor(userID), result.Lookup.Stale)
 	}
 
 	render.JSON(rw, req, resp)
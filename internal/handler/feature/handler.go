@@ -0,0 +1,356 @@
+package feature
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/internal/handler/mapper"
+	"avito-backend-trainee-2024/internal/handler/request"
+
+	handlerinternalutils "avito-backend-trainee-2024/internal/pkg/utils/handler"
+	handlerutils "avito-backend-trainee-2024/pkg/utils/handler"
+	sliceutils "avito-backend-trainee-2024/pkg/utils/slice"
+)
+
+type Service interface {
+	GetFeatureByID(ctx context.Context, id int) (*entity.Feature, error)
+	GetFeatures(ctx context.Context, offset, limit int) ([]*entity.Feature, error)
+	CreateFeature(ctx context.Context, feature entity.Feature) (*entity.Feature, error)
+	RenameFeature(ctx context.Context, id int, name string) (*entity.Feature, error)
+	SetContentSchema(ctx context.Context, id int, schema json.RawMessage) (*entity.Feature, error)
+	DeleteFeature(ctx context.Context, id int, cascade bool) (*entity.Feature, error)
+}
+
+type Middleware = func(http.Handler) http.Handler
+
+type Handler struct {
+	Service     Service
+	Middlewares []Middleware
+
+	logger    *logrus.Logger
+	validator *validator.Validate
+}
+
+func New(service Service, logger *logrus.Logger, validator *validator.Validate, middlewares ...Middleware) *Handler {
+	return &Handler{
+		Service:     service,
+		Middlewares: middlewares,
+		logger:      logger,
+		validator:   validator,
+	}
+}
+
+func (h *Handler) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(h.Middlewares...)
+
+		r.Get("/", h.GetFeatures)
+		r.Post("/", h.CreateFeature)
+		r.Get("/{id}", h.GetFeatureByID)
+		r.Patch("/{id}", h.RenameFeature)
+		r.Delete("/{id}", h.DeleteFeature)
+		r.Put("/{id}/content_schema", h.SetContentSchema)
+	})
+
+	return router
+}
+
+// GetFeatures godoc
+//
+//	@Summary		Get features
+//	@Description	Get features sorting by id
+//	@Security		JWT
+//	@Tags			Feature
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			offset	query		int	false	"Offset"
+//	@Param			limit	query		int	false	"Limit"
+//	@Success		200		{object}	[]response.GetFeatureResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/feature [get]
+func (h *Handler) GetFeatures(rw http.ResponseWriter, req *http.Request) {
+	paginationOpts := handlerinternalutils.GetPaginationOptsFromQuery(req, DefaultOffset, DefaultLimit)
+
+	if err := paginationOpts.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid pagination options provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
+
+		return
+	}
+
+	features, err := h.Service.GetFeatures(req.Context(), paginationOpts.Offset, paginationOpts.Limit)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching features: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(features, mapper.MapFeatureToGetFeatureResponse))
+}
+
+// GetFeatureByID godoc
+//
+//	@Summary		Get feature
+//	@Description	Get feature by id
+//	@Security		JWT
+//	@Tags			Feature
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			id	path		int	true	"id of the feature"
+//	@Success		200	{object}	response.GetFeatureResponse
+//	@Failure		401	{object}	handler.Problem
+//	@Failure		403	{object}	handler.Problem
+//	@Failure		404	{object}	handler.Problem
+//	@Failure		400	{object}	handler.Problem
+//	@Failure		500	{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/feature/{id} [get]
+func (h *Handler) GetFeatureByID(rw http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
+
+		return
+	}
+
+	feature, err := h.Service.GetFeatureByID(req.Context(), id)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching feature: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapFeatureToGetFeatureResponse(feature))
+}
+
+// CreateFeature godoc
+//
+//	@Summary		Create feature
+//	@Description	Create feature, content schema is optional
+//	@Security		JWT
+//	@Tags			Feature
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			input	body		request.CreateFeatureRequest	true	"feature info"
+//	@Success		201		{object}	response.GetFeatureResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/feature [post]
+func (h *Handler) CreateFeature(rw http.ResponseWriter, req *http.Request) {
+	var createReq request.CreateFeatureRequest
+
+	if err := render.DecodeJSON(req.Body, &createReq); err != nil {
+		msg := fmt.Sprintf("error occurred decoding request body to create feature request: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "request body is not a valid JSON")
+
+		return
+	}
+
+	if err := createReq.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid request: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
+
+		return
+	}
+
+	feature, err := h.Service.CreateFeature(req.Context(), mapper.MapCreateFeatureRequestToEntity(&createReq))
+	if err != nil {
+		msg := fmt.Sprintf("error occurred creating feature: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.Status(req, http.StatusCreated)
+	render.JSON(rw, req, mapper.MapFeatureToGetFeatureResponse(feature))
+}
+
+// RenameFeature godoc
+//
+//	@Summary		Rename feature
+//	@Description	Change name of the feature
+//	@Security		JWT
+//	@Tags			Feature
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			id		path		int								true	"id of the feature"
+//	@Param			input	body		request.UpdateFeatureRequest	true	"new feature info"
+//	@Success		200		{object}	response.GetFeatureResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		404		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/feature/{id} [patch]
+func (h *Handler) RenameFeature(rw http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
+
+		return
+	}
+
+	var updateReq request.UpdateFeatureRequest
+
+	if err = render.DecodeJSON(req.Body, &updateReq); err != nil {
+		msg := fmt.Sprintf("error occurred decoding request body to update feature request: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "request body is not a valid JSON")
+
+		return
+	}
+
+	if err = updateReq.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid request: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
+
+		return
+	}
+
+	feature, err := h.Service.RenameFeature(req.Context(), id, updateReq.Name)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred renaming feature: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapFeatureToGetFeatureResponse(feature))
+}
+
+// DeleteFeature godoc
+//
+//	@Summary		Delete feature
+//	@Description	Delete feature, feature having banners is deleted only with cascade=true which deletes its banners too
+//	@Security		JWT
+//	@Tags			Feature
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			id		path		int		true	"id of the feature"
+//	@Param			cascade	query		bool	false	"delete banners of the feature too"
+//	@Success		200		{object}	response.GetFeatureResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		404		{object}	handler.Problem
+//	@Failure		409		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/feature/{id} [delete]
+func (h *Handler) DeleteFeature(rw http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
+
+		return
+	}
+
+	var cascade bool
+
+	if req.URL.Query().Has("cascade") {
+		cascade, err = strconv.ParseBool(req.URL.Query().Get("cascade"))
+		if err != nil {
+			msg := fmt.Sprintf("invalid cascade query param provided: %v", err)
+
+			handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "cascade must be a boolean")
+
+			return
+		}
+	}
+
+	feature, err := h.Service.DeleteFeature(req.Context(), id, cascade)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred deleting feature: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapFeatureToGetFeatureResponse(feature))
+}
+
+// SetContentSchema godoc
+//
+//	@Summary		Set feature content schema
+//	@Description	Attach JSON schema to the feature, content of feature's banners is validated against it on create and update
+//	@Security		JWT
+//	@Tags			Feature
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			id		path		int		true	"id of the feature"
+//	@Param			input	body		object	true	"JSON schema"
+//	@Success		200		{object}	response.GetFeatureResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/feature/{id}/content_schema [put]
+func (h *Handler) SetContentSchema(rw http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
+
+		return
+	}
+
+	var schema json.RawMessage
+
+	if err = render.DecodeJSON(req.Body, &schema); err != nil {
+		msg := fmt.Sprintf("error occurred decoding request body to JSON schema: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "request body is not a valid JSON")
+
+		return
+	}
+
+	feature, err := h.Service.SetContentSchema(req.Context(), id, schema)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred setting content schema: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapFeatureToGetFeatureResponse(feature))
+	rw.WriteHeader(http.StatusOK)
+}
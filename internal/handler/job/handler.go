@@ -0,0 +1,90 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/sirupsen/logrus"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/internal/handler/mapper"
+
+	handlerutils "avito-backend-trainee-2024/pkg/utils/handler"
+)
+
+type Service interface {
+	GetJobByID(ctx context.Context, id int) (*entity.Job, error)
+}
+
+type Middleware = func(http.Handler) http.Handler
+
+type Handler struct {
+	Service     Service
+	Middlewares []Middleware
+
+	logger *logrus.Logger
+}
+
+func New(service Service, logger *logrus.Logger, middlewares ...Middleware) *Handler {
+	return &Handler{
+		Service:     service,
+		Middlewares: middlewares,
+		logger:      logger,
+	}
+}
+
+func (h *Handler) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(h.Middlewares...)
+
+		r.Get("/{id}", h.GetJobByID)
+	})
+
+	return router
+}
+
+// GetJobByID godoc
+//
+//	@Summary		Get job
+//	@Description	Get status and number of deleted banners of the deferred deletion job
+//	@Security		JWT
+//	@Tags			Job
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			id	path		int	true	"id of the job"
+//	@Success		200	{object}	response.GetJobResponse
+//	@Failure		401	{object}	handler.Problem
+//	@Failure		403	{object}	handler.Problem
+//	@Failure		400	{object}	handler.Problem
+//	@Failure		404	{object}	handler.Problem
+//	@Failure		500	{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/jobs/{id} [get]
+func (h *Handler) GetJobByID(rw http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
+
+		return
+	}
+
+	job, err := h.Service.GetJobByID(req.Context(), id)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching job: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapJobToGetJobResponse(job))
+	rw.WriteHeader(http.StatusOK)
+}
@@ -4,59 +4,190 @@ import (
 	"avito-backend-trainee-2024/internal/domain/entity"
 	"avito-backend-trainee-2024/internal/handler/request"
 	"avito-backend-trainee-2024/internal/handler/response"
+
+	handlerutils "avito-backend-trainee-2024/pkg/utils/handler"
 )
 
 func MapBannerToAdminBannerResponse(banner *entity.Banner) response.GetAdminBannerResponse {
-	return response.GetAdminBannerResponse{
-		ID:        banner.ID,
-		TagIDs:    banner.TagIDs,
-		FeatureID: banner.FeatureID,
-		GetContentResponse: response.GetContentResponse{
-			Title: banner.Content.Title,
-			Text:  banner.Content.Text,
-			Url:   banner.Content.Url,
-		},
-		IsActive:  banner.IsActive,
-		CreatedAt: banner.CreatedAt,
-		UpdatedAt: banner.UpdatedAt,
+	resp := response.GetAdminBannerResponse{
+		ID:          banner.ID,
+		TagIDs:      banner.TagIDs,
+		FeatureID:   banner.FeatureID,
+		Content:     banner.Content.Data,
+		Variants:    mapBannerVariantsToResponse(banner.Variants),
+		IsActive:    banner.IsActive,
+		ActiveFrom:  banner.ActiveFrom,
+		ActiveUntil: banner.ActiveUntil,
+		Priority:    banner.Priority,
+		IsDefault:   banner.IsDefault,
+		CreatedAt:   banner.CreatedAt,
+		UpdatedAt:   banner.UpdatedAt,
+	}
+
+	resp.RolloutPercent, resp.RolloutPrevious = mapBannerRolloutToResponse(banner.Rollout)
+
+	return resp
+}
+
+// MapBannerToUserBannerResponse returns content of the banner or of its variant shown to the user if it's not nil
+func MapBannerToUserBannerResponse(banner *entity.Banner, variant *entity.BannerVariant) response.GetUserBannerResponse {
+	if variant != nil {
+		return variant.Content
 	}
+
+	return banner.Content.Data
 }
 
-func MapBannerToUserBannerResponse(banner *entity.Banner) response.GetUserBannerResponse {
-	return response.GetUserBannerResponse{
-		GetContentResponse: response.GetContentResponse{
-			Title: banner.Content.Title,
-			Text:  banner.Content.Text,
-			Url:   banner.Content.Url,
-		}}
+func MapBannerToUserBannerResult(banner *entity.Banner, variant *entity.BannerVariant, stale bool) response.UserBannerResult {
+	result := response.UserBannerResult{
+		Content: MapBannerToUserBannerResponse(banner, variant),
+		Stale:   stale,
+	}
+
+	if variant != nil {
+		result.Variant = variant.Key
+	}
+
+	return result
+}
+
+func MapErrToUserBannerResult(status int, detail string) response.UserBannerResult {
+	return response.UserBannerResult{
+		Error: &response.UserBannerError{
+			Status: status,
+			Code:   handlerutils.CodeFromStatus(status),
+			Detail: detail,
+		},
+	}
 }
 
 func MapBannerToCreateBannerResponse(banner *entity.Banner) response.CreateBannerResponse {
 	return response.CreateBannerResponse{ID: banner.ID}
 }
 
-func MapCreateBannerRequestToEntity(req *request.CreateBannerRequest) entity.Banner {
-	return entity.Banner{
-		TagIDs:    req.TagIDs,
-		FeatureID: req.FeatureID,
-		Content: entity.Content{
-			Title: req.CreateContentRequest.Title,
-			Text:  req.CreateContentRequest.Text,
-			Url:   req.CreateContentRequest.Url,
-		},
-		IsActive: req.IsActive,
+func MapBannerVersionToBannerVersionResponse(version *entity.BannerVersion) response.GetBannerVersionResponse {
+	resp := response.GetBannerVersionResponse{
+		Version:     version.Version,
+		BannerID:    version.BannerID,
+		TagIDs:      version.TagIDs,
+		FeatureID:   version.FeatureID,
+		Content:     version.Content.Data,
+		Variants:    mapBannerVariantsToResponse(version.Variants),
+		IsActive:    version.IsActive,
+		ActiveFrom:  version.ActiveFrom,
+		ActiveUntil: version.ActiveUntil,
+		Priority:    version.Priority,
+		IsDefault:   version.IsDefault,
+		CreatedBy:   version.CreatedBy,
+		CreatedAt:   version.CreatedAt,
+	}
+
+	resp.RolloutPercent, resp.RolloutPrevious = mapBannerRolloutToResponse(version.Rollout)
+
+	return resp
+}
+
+// mapBannerRolloutToResponse returns rollout percent and previous content of the banner, nil rollout is full one
+func mapBannerRolloutToResponse(rollout *entity.BannerRollout) (int, *response.BannerSnapshotResponse) {
+	if rollout == nil {
+		return entity.FullRollout, nil
+	}
+
+	if rollout.Previous == nil {
+		return rollout.Percent, nil
+	}
+
+	return rollout.Percent, &response.BannerSnapshotResponse{
+		Content:  rollout.Previous.Content,
+		Variants: mapBannerVariantsToResponse(rollout.Previous.Variants),
 	}
 }
 
-func MapUpdateBannerRequestToEntity(req *request.UpdateBannerRequest) entity.Banner {
+func MapCreateBannerRequestToEntity(req *request.CreateBannerRequest, createdBy int) entity.Banner {
 	return entity.Banner{
 		TagIDs:    req.TagIDs,
 		FeatureID: req.FeatureID,
 		Content: entity.Content{
-			Title: req.UpdateContentRequest.Title,
-			Text:  req.UpdateContentRequest.Text,
-			Url:   req.UpdateContentRequest.Url,
+			Data: req.Content,
+		},
+		Variants: mapBannerVariantRequestsToEntity(req.Variants),
+		Activity: entity.Activity{
+			IsActive:    req.IsActive,
+			ActiveFrom:  req.ActiveFrom,
+			ActiveUntil: req.ActiveUntil,
 		},
-		IsActive: req.IsActive,
+		Priority:  req.Priority,
+		IsDefault: req.IsDefault,
+		Rollout:   mapRolloutPercentToEntity(req.RolloutPercent),
+		UpdatedBy: createdBy,
+	}
+}
+
+func MapUpdateBannerRequestToEntity(req *request.UpdateBannerRequest, updatedBy int) entity.BannerUpdate {
+	return entity.BannerUpdate{
+		TagIDs:      req.TagIDs,
+		FeatureID:   req.FeatureID,
+		Content:     req.Content,
+		Variants:    mapBannerVariantRequestsToEntity(req.Variants),
+		IsActive:    req.IsActive,
+		ActiveFrom:  mapOptionalTimeToEntity(req.ActiveFrom),
+		ActiveUntil: mapOptionalTimeToEntity(req.ActiveUntil),
+		Priority:    req.Priority,
+		IsDefault:   req.IsDefault,
+		Rollout:     mapRolloutPercentToEntity(req.RolloutPercent),
+		UpdatedBy:   updatedBy,
+	}
+}
+
+// mapOptionalTimeToEntity keeps omitted bound nil, in update request it means the bound isn't changed
+func mapOptionalTimeToEntity(t request.OptionalTime) *entity.TimeBound {
+	if !t.Set {
+		return nil
+	}
+
+	return &entity.TimeBound{At: t.Time}
+}
+
+// mapRolloutPercentToEntity keeps omitted percent nil, in update request it means rollout isn't changed
+func mapRolloutPercentToEntity(percent *int) *entity.BannerRollout {
+	if percent == nil {
+		return nil
 	}
+
+	return &entity.BannerRollout{Percent: *percent}
+}
+
+// mapBannerVariantRequestsToEntity keeps nil variants nil, in update request they mean variants aren't changed
+func mapBannerVariantRequestsToEntity(reqs []request.BannerVariantRequest) []entity.BannerVariant {
+	if reqs == nil {
+		return nil
+	}
+
+	variants := make([]entity.BannerVariant, 0, len(reqs))
+	for _, req := range reqs {
+		variants = append(variants, entity.BannerVariant{
+			Key:     req.Key,
+			Weight:  req.Weight,
+			Content: req.Content,
+		})
+	}
+
+	return variants
+}
+
+func mapBannerVariantsToResponse(variants []entity.BannerVariant) []response.BannerVariantResponse {
+	if variants == nil {
+		return nil
+	}
+
+	resp := make([]response.BannerVariantResponse, 0, len(variants))
+	for _, variant := range variants {
+		resp = append(resp, response.BannerVariantResponse{
+			Key:     variant.Key,
+			Weight:  variant.Weight,
+			Content: variant.Content,
+		})
+	}
+
+	return resp
 }
@@ -0,0 +1,18 @@
+package mapper
+
+import (
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/internal/handler/response"
+)
+
+func MapCacheStatsToGetCacheStatsResponse(stats entity.CacheStats) response.GetCacheStatsResponse {
+	return response.GetCacheStatsResponse{
+		IndexHits:        stats.IndexHits,
+		Hits:             stats.Hits,
+		StaleHits:        stats.StaleHits,
+		NegativeHits:     stats.NegativeHits,
+		Misses:           stats.Misses,
+		Bypasses:         stats.Bypasses,
+		StaleIfErrorHits: stats.StaleIfErrorHits,
+	}
+}
@@ -0,0 +1,24 @@
+package mapper
+
+import (
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/internal/handler/request"
+	"avito-backend-trainee-2024/internal/handler/response"
+)
+
+func MapFeatureToGetFeatureResponse(feature *entity.Feature) response.GetFeatureResponse {
+	return response.GetFeatureResponse{
+		ID:            feature.ID,
+		Name:          feature.Name,
+		ContentSchema: feature.ContentSchema,
+		CreatedAt:     feature.CreatedAt,
+		UpdatedAt:     feature.UpdatedAt,
+	}
+}
+
+func MapCreateFeatureRequestToEntity(req *request.CreateFeatureRequest) entity.Feature {
+	return entity.Feature{
+		Name:          req.Name,
+		ContentSchema: req.ContentSchema,
+	}
+}
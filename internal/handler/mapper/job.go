@@ -0,0 +1,23 @@
+package mapper
+
+import (
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/internal/handler/response"
+)
+
+func MapJobToCreateJobResponse(job *entity.Job) response.CreateJobResponse {
+	return response.CreateJobResponse{ID: job.ID}
+}
+
+func MapJobToGetJobResponse(job *entity.Job) response.GetJobResponse {
+	return response.GetJobResponse{
+		ID:        job.ID,
+		FeatureID: job.FeatureID,
+		TagID:     job.TagID,
+		Status:    job.Status,
+		Deleted:   job.Deleted,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+}
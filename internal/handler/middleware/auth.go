@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -28,7 +29,7 @@ func JWTAuthentication(headerName, secret string, logger *logrus.Logger) Handler
 			if authHeader == "" {
 				msg := fmt.Sprintf("%v header is empty", headerName)
 
-				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
+				handlerutils.WriteErrResponseAndLog(rw, req, logger, http.StatusUnauthorized, msg, msg)
 				return
 			}
 
@@ -38,7 +39,7 @@ func JWTAuthentication(headerName, secret string, logger *logrus.Logger) Handler
 			if err != nil {
 				msg := fmt.Sprintf("error occurred validating token: %v", err)
 
-				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
+				handlerutils.WriteErrResponseAndLog(rw, req, logger, http.StatusUnauthorized, msg, "invalid token")
 				return
 			}
 
@@ -46,7 +47,7 @@ func JWTAuthentication(headerName, secret string, logger *logrus.Logger) Handler
 			if err != nil {
 				msg := fmt.Sprintf("invalid payload: not contains id: %v", err)
 
-				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
+				handlerutils.WriteErrResponseAndLog(rw, req, logger, http.StatusUnauthorized, msg, "invalid token payload")
 				return
 			}
 
@@ -54,7 +55,7 @@ func JWTAuthentication(headerName, secret string, logger *logrus.Logger) Handler
 			if err != nil {
 				msg := fmt.Sprintf("invalid payload: not contains username: %v", err)
 
-				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
+				handlerutils.WriteErrResponseAndLog(rw, req, logger, http.StatusUnauthorized, msg, "invalid token payload")
 				return
 			}
 
@@ -62,13 +63,23 @@ func JWTAuthentication(headerName, secret string, logger *logrus.Logger) Handler
 			if err != nil {
 				msg := fmt.Sprintf("invalid payload: not contains is_admin: %v", err)
 
-				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
+				handlerutils.WriteErrResponseAndLog(rw, req, logger, http.StatusUnauthorized, msg, "invalid token payload")
+				return
+			}
+
+			// tokens issued before always_fresh was introduced don't contain it
+			alwaysFresh, err := maputils.GetBoolFromAnyMap(payload, "always_fresh")
+			if err != nil && !errors.Is(err, maputils.ErrNoSuchKey) {
+				msg := fmt.Sprintf("invalid payload: invalid always_fresh: %v", err)
+
+				handlerutils.WriteErrResponseAndLog(rw, req, logger, http.StatusUnauthorized, msg, "invalid token payload")
 				return
 			}
 
 			req.Header.Set("id", strconv.Itoa(id))
 			req.Header.Set("username", username)
 			req.Header.Set("is_admin", fmt.Sprintf("%v", isAdmin))
+			req.Header.Set("always_fresh", fmt.Sprintf("%v", alwaysFresh))
 
 			next.ServeHTTP(rw, req)
 		})
@@ -81,7 +92,7 @@ func AdminAuthorization(logger *logrus.Logger) Handler {
 			if req.Header.Get("is_admin") != "true" {
 				msg := "only admin allowed to call this method"
 
-				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusForbidden, msg, msg)
+				handlerutils.WriteErrResponseAndLog(rw, req, logger, http.StatusForbidden, msg, msg)
 				return
 			}
 
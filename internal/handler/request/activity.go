@@ -0,0 +1,31 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrInvalidActiveWindow = errors.New("active_until must be after active_from")
+
+// validateActiveWindow checks that activation window is not empty if both bounds provided
+func validateActiveWindow(from, until *time.Time) error {
+	if from != nil && until != nil && !until.After(*from) {
+		return ErrInvalidActiveWindow
+	}
+
+	return nil
+}
+
+// OptionalTime is a time field of partial update, it tells omitted field (Set is false) from explicit null
+// (Set is true and Time is nil)
+type OptionalTime struct {
+	Set  bool
+	Time *time.Time
+}
+
+func (t *OptionalTime) UnmarshalJSON(data []byte) error {
+	t.Set = true
+
+	return json.Unmarshal(data, &t.Time)
+}
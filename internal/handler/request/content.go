@@ -0,0 +1,18 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+var ErrContentIsNotObject = errors.New("content must be a JSON object")
+
+// validateContent checks that provided content is a JSON object
+func validateContent(content json.RawMessage) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		return ErrContentIsNotObject
+	}
+
+	return nil
+}
@@ -1,18 +1,43 @@
 package request
 
-import "github.com/go-playground/validator/v10"
+import (
+	"encoding/json"
+	"time"
 
-type CreateContentRequest struct {
-	Title string `json:"title" validate:"required,min=1"`
-	Text  string `json:"text" validate:"required,min=1"`
-	Url   string `json:"url" validate:"required,min=1,url"`
-}
+	"github.com/go-playground/validator/v10"
+)
 
 type CreateBannerRequest struct {
-	TagIDs    []int `json:"tag_ids" validate:"required,min=1"`
-	FeatureID int   `json:"feature_id" validate:"required,min=0"`
-	CreateContentRequest
-	IsActive bool `json:"is_active"`
+	TagIDs    []int           `json:"tag_ids" validate:"required,min=1"`
+	FeatureID int             `json:"feature_id" validate:"required,min=0"`
+	Content   json.RawMessage `json:"content" validate:"required" swaggertype:"object"`
+	IsActive  bool            `json:"is_active"`
+	// Variants are alternative contents, each user always gets the same one with probability proportional to weight
+	Variants []BannerVariantRequest `json:"variants,omitempty" validate:"omitempty,dive"`
+	// Priority decides which of banners matching user tags is shown, higher wins
+	Priority int `json:"priority"`
+	// IsDefault makes banner shown for the feature when no banner matches user tags
+	IsDefault bool `json:"is_default"`
+	// RolloutPercent is share of users who see the banner, omitted means all users
+	RolloutPercent *int `json:"rollout_percent,omitempty" validate:"omitempty,min=0,max=100"`
+
+	// optional activation window, banner is shown to users only within it
+	ActiveFrom  *time.Time `json:"active_from,omitempty"`
+	ActiveUntil *time.Time `json:"active_until,omitempty"`
 }
 
-func (br *CreateBannerRequest) Validate(valid *validator.Validate) error { return valid.Struct(br) }
+func (br *CreateBannerRequest) Validate(valid *validator.Validate) error {
+	if err := valid.Struct(br); err != nil {
+		return err
+	}
+
+	if err := validateActiveWindow(br.ActiveFrom, br.ActiveUntil); err != nil {
+		return err
+	}
+
+	if err := validateVariants(br.Variants); err != nil {
+		return err
+	}
+
+	return validateContent(br.Content)
+}
@@ -0,0 +1,16 @@
+package request
+
+import (
+	"encoding/json"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type CreateFeatureRequest struct {
+	Name string `json:"name" validate:"required,min=1"`
+
+	// optional JSON schema content of feature's banners must match
+	ContentSchema json.RawMessage `json:"content_schema,omitempty" swaggertype:"object"`
+}
+
+func (fr *CreateFeatureRequest) Validate(valid *validator.Validate) error { return valid.Struct(fr) }
@@ -0,0 +1,15 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type CreateTagRequest struct {
+	Name string `json:"name" validate:"required,min=1"`
+}
+
+func (tr *CreateTagRequest) Validate(valid *validator.Validate) error { return valid.Struct(tr) }
+
+type CreateTagsRequest struct {
+	Names []string `json:"names" validate:"required,min=1,dive,required,min=1"`
+}
+
+func (tr *CreateTagsRequest) Validate(valid *validator.Validate) error { return valid.Struct(tr) }
@@ -1,18 +1,49 @@
 package request
 
-import "github.com/go-playground/validator/v10"
+import (
+	"encoding/json"
 
-type UpdateContentRequest struct {
-	Title string `json:"title"`
-	Text  string `json:"text"`
-	Url   string `json:"url" validate:"omitempty,url"`
-}
+	"github.com/go-playground/validator/v10"
+)
 
 type UpdateBannerRequest struct {
-	TagIDs    []int `json:"tag_ids"`
-	FeatureID int   `json:"feature_id"`
-	UpdateContentRequest
-	IsActive bool `json:"is_active"`
+	TagIDs    []int           `json:"tag_ids"`
+	FeatureID int             `json:"feature_id"`
+	Content   json.RawMessage `json:"content,omitempty" swaggertype:"object"`
+	// omitted activity flag is kept
+	IsActive *bool `json:"is_active,omitempty"`
+	// omitted variants are kept, empty list removes them
+	Variants []BannerVariantRequest `json:"variants" validate:"omitempty,dive"`
+	// omitted priority and default flag are kept
+	Priority  *int  `json:"priority,omitempty"`
+	IsDefault *bool `json:"is_default,omitempty"`
+	// omitted rollout percent is kept, replacing content of active banner with percent below 100 keeps showing
+	// its current content to the rest of users until rollout reaches 100
+	RolloutPercent *int `json:"rollout_percent,omitempty" validate:"omitempty,min=0,max=100"`
+
+	// omitted bound of activation window is kept, null removes it
+	ActiveFrom  OptionalTime `json:"active_from" swaggertype:"string" format:"date-time" extensions:"x-nullable"`
+	ActiveUntil OptionalTime `json:"active_until" swaggertype:"string" format:"date-time" extensions:"x-nullable"`
 }
 
-func (br *UpdateBannerRequest) Validate(valid *validator.Validate) error { return valid.Struct(br) }
+func (br *UpdateBannerRequest) Validate(valid *validator.Validate) error {
+	if err := valid.Struct(br); err != nil {
+		return err
+	}
+
+	// the window is checked against kept bounds by the service
+	if err := validateActiveWindow(br.ActiveFrom.Time, br.ActiveUntil.Time); err != nil {
+		return err
+	}
+
+	if err := validateVariants(br.Variants); err != nil {
+		return err
+	}
+
+	// content is optional, but if provided it must be an object
+	if len(br.Content) == 0 {
+		return nil
+	}
+
+	return validateContent(br.Content)
+}
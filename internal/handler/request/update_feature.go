@@ -0,0 +1,9 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type UpdateFeatureRequest struct {
+	Name string `json:"name" validate:"required,min=1"`
+}
+
+func (fr *UpdateFeatureRequest) Validate(valid *validator.Validate) error { return valid.Struct(fr) }
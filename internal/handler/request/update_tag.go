@@ -0,0 +1,9 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type UpdateTagRequest struct {
+	Name string `json:"name" validate:"required,min=1"`
+}
+
+func (tr *UpdateTagRequest) Validate(valid *validator.Validate) error { return valid.Struct(tr) }
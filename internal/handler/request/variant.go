@@ -0,0 +1,34 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrDuplicateVariantKey = errors.New("variant keys must be unique")
+
+type BannerVariantRequest struct {
+	Key     string          `json:"key" validate:"required,max=64"`
+	Weight  int             `json:"weight" validate:"min=1"`
+	Content json.RawMessage `json:"content" validate:"required" swaggertype:"object"`
+}
+
+// validateVariants checks that variant keys are unique and their contents are JSON objects
+func validateVariants(variants []BannerVariantRequest) error {
+	keys := make(map[string]struct{}, len(variants))
+
+	for _, variant := range variants {
+		if _, ok := keys[variant.Key]; ok {
+			return fmt.Errorf("%w: %v", ErrDuplicateVariantKey, variant.Key)
+		}
+
+		keys[variant.Key] = struct{}{}
+
+		if err := validateContent(variant.Content); err != nil {
+			return fmt.Errorf("variant %v: %w", variant.Key, err)
+		}
+	}
+
+	return nil
+}
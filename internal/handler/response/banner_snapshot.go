@@ -0,0 +1,8 @@
+package response
+
+import "encoding/json"
+
+type BannerSnapshotResponse struct {
+	Content  json.RawMessage         `json:"content" swaggertype:"object"`
+	Variants []BannerVariantResponse `json:"variants,omitempty"`
+}
@@ -0,0 +1,9 @@
+package response
+
+import "encoding/json"
+
+type BannerVariantResponse struct {
+	Key     string          `json:"key"`
+	Weight  int             `json:"weight"`
+	Content json.RawMessage `json:"content" swaggertype:"object"`
+}
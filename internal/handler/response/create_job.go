@@ -0,0 +1,5 @@
+package response
+
+type CreateJobResponse struct {
+	ID int `json:"job_id"`
+}
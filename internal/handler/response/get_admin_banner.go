@@ -1,19 +1,24 @@
 package response
 
-import "time"
-
-type GetContentResponse struct {
-	Title string `json:"title"`
-	Text  string `json:"text"`
-	Url   string `json:"url"`
-}
+import (
+	"encoding/json"
+	"time"
+)
 
 type GetAdminBannerResponse struct {
-	ID        int   `json:"banner_id"`
-	TagIDs    []int `json:"tag_ids"`
-	FeatureID int   `json:"feature_id"`
-	GetContentResponse
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          int                     `json:"banner_id"`
+	TagIDs      []int                   `json:"tag_ids"`
+	FeatureID   int                     `json:"feature_id"`
+	Content     json.RawMessage         `json:"content" swaggertype:"object"`
+	Variants    []BannerVariantResponse `json:"variants,omitempty"`
+	IsActive    bool                    `json:"is_active"`
+	ActiveFrom  *time.Time              `json:"active_from,omitempty"`
+	ActiveUntil *time.Time              `json:"active_until,omitempty"`
+	Priority    int                     `json:"priority"`
+	IsDefault   bool                    `json:"is_default"`
+	// RolloutPercent is share of users who see the banner, the rest see RolloutPrevious if it's present
+	RolloutPercent  int                     `json:"rollout_percent"`
+	RolloutPrevious *BannerSnapshotResponse `json:"rollout_previous,omitempty"`
+	CreatedAt       time.Time               `json:"created_at"`
+	UpdatedAt       time.Time               `json:"updated_at"`
 }
@@ -0,0 +1,25 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type GetBannerVersionResponse struct {
+	Version     int                     `json:"version"`
+	BannerID    int                     `json:"banner_id"`
+	TagIDs      []int                   `json:"tag_ids"`
+	FeatureID   int                     `json:"feature_id"`
+	Content     json.RawMessage         `json:"content" swaggertype:"object"`
+	Variants    []BannerVariantResponse `json:"variants,omitempty"`
+	IsActive    bool                    `json:"is_active"`
+	ActiveFrom  *time.Time              `json:"active_from,omitempty"`
+	ActiveUntil *time.Time              `json:"active_until,omitempty"`
+	Priority    int                     `json:"priority"`
+	IsDefault   bool                    `json:"is_default"`
+	// RolloutPercent and RolloutPrevious are restored along with the rest of the version
+	RolloutPercent  int                     `json:"rollout_percent"`
+	RolloutPrevious *BannerSnapshotResponse `json:"rollout_previous,omitempty"`
+	CreatedBy       int                     `json:"created_by"`
+	CreatedAt       time.Time               `json:"created_at"`
+}
@@ -0,0 +1,11 @@
+package response
+
+type GetCacheStatsResponse struct {
+	IndexHits        int64 `json:"index_hits"`
+	Hits             int64 `json:"hits"`
+	StaleHits        int64 `json:"stale_hits"`
+	NegativeHits     int64 `json:"negative_hits"`
+	Misses           int64 `json:"misses"`
+	Bypasses         int64 `json:"bypasses"`
+	StaleIfErrorHits int64 `json:"stale_if_error_hits"`
+}
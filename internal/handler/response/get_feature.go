@@ -0,0 +1,14 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type GetFeatureResponse struct {
+	ID            int             `json:"feature_id"`
+	Name          string          `json:"name"`
+	ContentSchema json.RawMessage `json:"content_schema" swaggertype:"object"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+type GetJobResponse struct {
+	ID        int       `json:"job_id"`
+	FeatureID int       `json:"feature_id,omitempty"`
+	TagID     int       `json:"tag_id,omitempty"`
+	Status    string    `json:"status"`
+	Deleted   int       `json:"deleted"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,10 @@
+package response
+
+import "time"
+
+type GetTagResponse struct {
+	ID        int       `json:"tag_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -1,5 +1,6 @@
 package response
 
-type GetUserBannerResponse struct {
-	GetContentResponse
-}
+import "encoding/json"
+
+// GetUserBannerResponse is a banner content returned verbatim
+type GetUserBannerResponse = json.RawMessage
@@ -0,0 +1,21 @@
+package response
+
+import "encoding/json"
+
+// GetUserBannersResponse maps feature id to its banner or error of its lookup
+type GetUserBannersResponse map[int]UserBannerResult
+
+type UserBannerResult struct {
+	Content json.RawMessage `json:"content,omitempty" swaggertype:"object"`
+	// Variant is a key of content variant shown to the user, empty if banner has no variants
+	Variant string `json:"variant,omitempty"`
+	// Stale is true if database is unavailable and last known banner is returned
+	Stale bool             `json:"stale,omitempty"`
+	Error *UserBannerError `json:"error,omitempty"`
+}
+
+type UserBannerError struct {
+	Status int    `json:"status"`
+	Code   string `json:"code"`
+	Detail string `json:"detail,omitempty"`
+}
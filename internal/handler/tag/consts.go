@@ -0,0 +1,6 @@
+package tag
+
+const (
+	DefaultOffset = 0
+	DefaultLimit  = 100
+)
@@ -0,0 +1,353 @@
+package tag
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/internal/handler/mapper"
+	"avito-backend-trainee-2024/internal/handler/request"
+
+	handlerinternalutils "avito-backend-trainee-2024/internal/pkg/utils/handler"
+	handlerutils "avito-backend-trainee-2024/pkg/utils/handler"
+	sliceutils "avito-backend-trainee-2024/pkg/utils/slice"
+)
+
+type Service interface {
+	GetTagByID(ctx context.Context, id int) (*entity.Tag, error)
+	GetTags(ctx context.Context, offset, limit int) ([]*entity.Tag, error)
+	CreateTag(ctx context.Context, name string) (*entity.Tag, error)
+	CreateTags(ctx context.Context, names []string) ([]*entity.Tag, error)
+	RenameTag(ctx context.Context, id int, name string) (*entity.Tag, error)
+	DeleteTag(ctx context.Context, id int, cascade bool) (*entity.Tag, error)
+}
+
+type Middleware = func(http.Handler) http.Handler
+
+type Handler struct {
+	Service     Service
+	Middlewares []Middleware
+
+	logger    *logrus.Logger
+	validator *validator.Validate
+}
+
+func New(service Service, logger *logrus.Logger, validator *validator.Validate, middlewares ...Middleware) *Handler {
+	return &Handler{
+		Service:     service,
+		Middlewares: middlewares,
+		logger:      logger,
+		validator:   validator,
+	}
+}
+
+func (h *Handler) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(h.Middlewares...)
+
+		r.Get("/", h.GetTags)
+		r.Post("/", h.CreateTag)
+		r.Post("/bulk", h.CreateTags)
+		r.Get("/{id}", h.GetTagByID)
+		r.Patch("/{id}", h.RenameTag)
+		r.Delete("/{id}", h.DeleteTag)
+	})
+
+	return router
+}
+
+// GetTags godoc
+//
+//	@Summary		Get tags
+//	@Description	Get tags sorting by id
+//	@Security		JWT
+//	@Tags			Tag
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			offset	query		int	false	"Offset"
+//	@Param			limit	query		int	false	"Limit"
+//	@Success		200		{object}	[]response.GetTagResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/tag [get]
+func (h *Handler) GetTags(rw http.ResponseWriter, req *http.Request) {
+	paginationOpts := handlerinternalutils.GetPaginationOptsFromQuery(req, DefaultOffset, DefaultLimit)
+
+	if err := paginationOpts.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid pagination options provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
+
+		return
+	}
+
+	tags, err := h.Service.GetTags(req.Context(), paginationOpts.Offset, paginationOpts.Limit)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching tags: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(tags, mapper.MapTagToGetTagResponse))
+}
+
+// GetTagByID godoc
+//
+//	@Summary		Get tag
+//	@Description	Get tag by id
+//	@Security		JWT
+//	@Tags			Tag
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			id	path		int	true	"id of the tag"
+//	@Success		200	{object}	response.GetTagResponse
+//	@Failure		401	{object}	handler.Problem
+//	@Failure		403	{object}	handler.Problem
+//	@Failure		404	{object}	handler.Problem
+//	@Failure		400	{object}	handler.Problem
+//	@Failure		500	{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/tag/{id} [get]
+func (h *Handler) GetTagByID(rw http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
+
+		return
+	}
+
+	tag, err := h.Service.GetTagByID(req.Context(), id)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching tag: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapTagToGetTagResponse(tag))
+}
+
+// CreateTag godoc
+//
+//	@Summary		Create tag
+//	@Description	Create tag
+//	@Security		JWT
+//	@Tags			Tag
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			input	body		request.CreateTagRequest	true	"tag info"
+//	@Success		201		{object}	response.GetTagResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/tag [post]
+func (h *Handler) CreateTag(rw http.ResponseWriter, req *http.Request) {
+	var createReq request.CreateTagRequest
+
+	if err := render.DecodeJSON(req.Body, &createReq); err != nil {
+		msg := fmt.Sprintf("error occurred decoding request body to create tag request: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "request body is not a valid JSON")
+
+		return
+	}
+
+	if err := createReq.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid request: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
+
+		return
+	}
+
+	tag, err := h.Service.CreateTag(req.Context(), createReq.Name)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred creating tag: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.Status(req, http.StatusCreated)
+	render.JSON(rw, req, mapper.MapTagToGetTagResponse(tag))
+}
+
+// CreateTags godoc
+//
+//	@Summary		Create tags
+//	@Description	Create several tags at once, either all of them are created or none
+//	@Security		JWT
+//	@Tags			Tag
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			input	body		request.CreateTagsRequest	true	"names of the tags"
+//	@Success		201		{object}	[]response.GetTagResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/tag/bulk [post]
+func (h *Handler) CreateTags(rw http.ResponseWriter, req *http.Request) {
+	var createReq request.CreateTagsRequest
+
+	if err := render.DecodeJSON(req.Body, &createReq); err != nil {
+		msg := fmt.Sprintf("error occurred decoding request body to create tags request: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "request body is not a valid JSON")
+
+		return
+	}
+
+	if err := createReq.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid request: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
+
+		return
+	}
+
+	tags, err := h.Service.CreateTags(req.Context(), createReq.Names)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred creating tags: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.Status(req, http.StatusCreated)
+	render.JSON(rw, req, sliceutils.Map(tags, mapper.MapTagToGetTagResponse))
+}
+
+// RenameTag godoc
+//
+//	@Summary		Rename tag
+//	@Description	Change name of the tag
+//	@Security		JWT
+//	@Tags			Tag
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			id		path		int							true	"id of the tag"
+//	@Param			input	body		request.UpdateTagRequest	true	"new tag info"
+//	@Success		200		{object}	response.GetTagResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		404		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/tag/{id} [patch]
+func (h *Handler) RenameTag(rw http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
+
+		return
+	}
+
+	var updateReq request.UpdateTagRequest
+
+	if err = render.DecodeJSON(req.Body, &updateReq); err != nil {
+		msg := fmt.Sprintf("error occurred decoding request body to update tag request: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "request body is not a valid JSON")
+
+		return
+	}
+
+	if err = updateReq.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid request: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "invalid request", handlerutils.FieldErrors(err)...)
+
+		return
+	}
+
+	tag, err := h.Service.RenameTag(req.Context(), id, updateReq.Name)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred renaming tag: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapTagToGetTagResponse(tag))
+}
+
+// DeleteTag godoc
+//
+//	@Summary		Delete tag
+//	@Description	Delete tag, tag of some banners is deleted only with cascade=true which deletes these banners too
+//	@Security		JWT
+//	@Tags			Tag
+//	@Accept			json
+//	@Produce		json
+//	@Param token 	header string true "admin auth token"
+//	@Param			id		path		int		true	"id of the tag"
+//	@Param			cascade	query		bool	false	"delete banners having the tag too"
+//	@Success		200		{object}	response.GetTagResponse
+//	@Failure		401		{object}	handler.Problem
+//	@Failure		403		{object}	handler.Problem
+//	@Failure		404		{object}	handler.Problem
+//	@Failure		409		{object}	handler.Problem
+//	@Failure		400		{object}	handler.Problem
+//	@Failure		500		{object}	handler.Problem
+//	@Router			/avito-trainee/api/v1/tag/{id} [delete]
+func (h *Handler) DeleteTag(rw http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+	if err != nil {
+		msg := fmt.Sprintf("inavlid url param for id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "id must be an integer")
+
+		return
+	}
+
+	var cascade bool
+
+	if req.URL.Query().Has("cascade") {
+		cascade, err = strconv.ParseBool(req.URL.Query().Get("cascade"))
+		if err != nil {
+			msg := fmt.Sprintf("invalid cascade query param provided: %v", err)
+
+			handlerutils.WriteErrResponseAndLog(rw, req, h.logger, http.StatusBadRequest, msg, "cascade must be a boolean")
+
+			return
+		}
+	}
+
+	tag, err := h.Service.DeleteTag(req.Context(), id, cascade)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred deleting tag: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, req, h.logger, handlerutils.StatusFromErr(err), msg, err.Error())
+
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapTagToGetTagResponse(tag))
+}
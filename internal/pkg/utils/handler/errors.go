@@ -0,0 +1,7 @@
+package handler
+
+import "avito-backend-trainee-2024/pkg/errs"
+
+var (
+	ErrInvalidBannerStatus = errs.New(errs.ErrInvalid, "status must be one of: scheduled, live, expired")
+)
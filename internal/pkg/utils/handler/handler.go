@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 
+	"avito-backend-trainee-2024/internal/domain/entity"
 	"avito-backend-trainee-2024/internal/handler/request"
 
 	handlerutils "avito-backend-trainee-2024/pkg/utils/handler"
@@ -26,3 +27,15 @@ func GetPaginationOptsFromQuery(req *http.Request, defaultOffset int, defaultLim
 
 	return paginationOpts
 }
+
+// GetBannerStatusFromQuery returns banner status filter from 'status' query param, empty status means any
+func GetBannerStatusFromQuery(req *http.Request) (entity.BannerStatus, error) {
+	status := req.URL.Query().Get("status")
+
+	switch status {
+	case "", entity.BannerStatusScheduled, entity.BannerStatusLive, entity.BannerStatusExpired:
+		return status, nil
+	default:
+		return "", ErrInvalidBannerStatus
+	}
+}
@@ -1,7 +1,13 @@
 package banner
 
-import "errors"
+import "avito-backend-trainee-2024/pkg/errs"
 
 var (
-	ErrNoSuchBanner = errors.New("no such banner")
+	ErrNoSuchBanner        = errs.New(errs.ErrNotFound, "no such banner")
+	ErrNoSuchBannerVersion = errs.New(errs.ErrNotFound, "no such banner version")
+	ErrBannerExists        = errs.New(errs.ErrConflict, "banner with this feature and tags already exists")
+	ErrDefaultBannerExists = errs.New(errs.ErrConflict, "feature already has default banner")
+	// ErrVersionFeatureDeleted and ErrVersionTagDeleted are returned if restored version refers to deleted feature or tag
+	ErrVersionFeatureDeleted = errs.New(errs.ErrConflict, "feature of banner version was deleted")
+	ErrVersionTagDeleted     = errs.New(errs.ErrConflict, "tag of banner version was deleted")
 )
@@ -0,0 +1,83 @@
+package banner
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+)
+
+// bannerChangesChannel is a channel banner_change_notify trigger sends notifications to
+const bannerChangesChannel = "banner_changes"
+
+type bannerKeyPayload struct {
+	FeatureID int   `json:"feature_id"`
+	TagIDs    []int `json:"tag_ids"`
+	IsDefault bool  `json:"is_default"`
+}
+
+func (p *bannerKeyPayload) toEntity() *entity.BannerKey {
+	if p == nil {
+		return nil
+	}
+
+	return &entity.BannerKey{
+		FeatureID: p.FeatureID,
+		TagIDs:    p.TagIDs,
+		IsDefault: p.IsDefault,
+	}
+}
+
+type bannerChangePayload struct {
+	BannerID int               `json:"banner_id"`
+	Old      *bannerKeyPayload `json:"old"`
+	New      *bannerKeyPayload `json:"new"`
+}
+
+// ListenBannerChanges subscribes to changes of banners made by any instance and calls onChange for each of them
+// until ctx is done or connection is lost. onListen is called once subscription is established.
+// Connection used for listening is taken from the pool and held until return
+func (r *Repo) ListenBannerChanges(ctx context.Context, onListen func(), onChange func(change entity.BannerChange)) error {
+	conn, err := r.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+bannerChangesChannel); err != nil {
+			return err
+		}
+
+		// connection returns to the pool, it must not receive notifications there
+		defer func() {
+			_, _ = pgxConn.Exec(context.WithoutCancel(ctx), "UNLISTEN "+bannerChangesChannel)
+		}()
+
+		onListen()
+
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+
+			var payload bannerChangePayload
+
+			if err = json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+				return err
+			}
+
+			onChange(entity.BannerChange{
+				BannerID: payload.BannerID,
+				Old:      payload.Old.toEntity(),
+				New:      payload.New.toEntity(),
+			})
+		}
+	})
+}
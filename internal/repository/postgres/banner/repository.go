@@ -3,19 +3,36 @@ package banner
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"encoding/json"
+	"errors"
 	"math"
 	"slices"
+	"strconv"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 
 	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/pkg/errs"
 
-	sliceutils "avito-backend-trainee-2024/pkg/utils/slice"
-	stringutils "avito-backend-trainee-2024/pkg/utils/string"
+	pgutils "avito-backend-trainee-2024/pkg/utils/postgres"
 )
 
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+
+	featureTagsUniqueIndex = "banner_feature_id_tag_ids_key"
+	featureDefaultIndex    = "banner_feature_id_default_key"
+	featureForeignKey      = "banner_feature_id_fkey"
+	tagForeignKey          = "banner_tag_tag_id_fkey"
+)
+
+// psql builds queries with postgres placeholders, all values are passed to database as bound arguments
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
 type Repo struct {
 	DB *sqlx.DB
 }
@@ -26,73 +43,153 @@ func New(db *sqlx.DB) *Repo {
 	}
 }
 
-func setQueryForUpdateModel(updateModel entity.Banner) string {
-	setQuery := ""
-	isCommaNeeded := false
+// sortedTagIDs returns sorted copy of tag ids without duplicates, the way they are stored in banner.tag_ids
+func sortedTagIDs(tagIDs []int) []int {
+	sorted := slices.Clone(tagIDs)
+	slices.Sort(sorted)
+
+	return slices.Compact(sorted)
+}
+
+// encodeVariants returns variants as value of jsonb column, banner without variants has NULL there
+func encodeVariants(variants []entity.BannerVariant) (any, error) {
+	if len(variants) == 0 {
+		return nil, nil
+	}
 
-	if updateModel.Content.Title != "" {
-		setQuery += fmt.Sprintf("title = '%v'", updateModel.Content.Title)
-		isCommaNeeded = true
+	encoded, err := json.Marshal(variants)
+	if err != nil {
+		return nil, err
 	}
 
-	if updateModel.Content.Text != "" {
-		if isCommaNeeded {
-			setQuery += ", "
-		}
+	return json.RawMessage(encoded), nil
+}
 
-		setQuery += fmt.Sprintf("text = '%v'", updateModel.Content.Text)
-		isCommaNeeded = true
+// decodeVariants parses value of jsonb variants column, NULL means no variants
+func decodeVariants(raw []byte) ([]entity.BannerVariant, error) {
+	if len(raw) == 0 {
+		return nil, nil
 	}
 
-	if updateModel.Content.Url != "" {
-		if isCommaNeeded {
-			setQuery += ", "
+	var variants []entity.BannerVariant
+
+	if err := json.Unmarshal(raw, &variants); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+// encodeRollout returns rollout as values of rollout_percent and rollout_previous columns, banner without rollout
+// is shown to all users and has no previous content
+func encodeRollout(rollout *entity.BannerRollout) (int, any, error) {
+	if rollout == nil {
+		return entity.FullRollout, nil, nil
+	}
+
+	if rollout.Previous == nil {
+		return rollout.Percent, nil, nil
+	}
+
+	encoded, err := json.Marshal(rollout.Previous)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return rollout.Percent, json.RawMessage(encoded), nil
+}
+
+// decodeRollout parses values of rollout_percent and rollout_previous columns, full rollout without previous content
+// means no rollout
+func decodeRollout(percent int, previous []byte) (*entity.BannerRollout, error) {
+	if percent >= entity.FullRollout && previous == nil {
+		return nil, nil
+	}
+
+	rollout := entity.BannerRollout{Percent: percent}
+
+	if previous != nil {
+		if err := json.Unmarshal(previous, &rollout.Previous); err != nil {
+			return nil, err
 		}
+	}
+
+	return &rollout, nil
+}
 
-		setQuery += fmt.Sprintf("url = '%v'", updateModel.Content.Url)
+// mapVersionReferenceViolation returns ErrVersionFeatureDeleted or ErrVersionTagDeleted if err is violation of
+// foreign key to feature or tag, it happens if they are deleted while the version is being restored
+func mapVersionReferenceViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != foreignKeyViolationCode {
+		return err
 	}
 
-	return setQuery
+	switch pgErr.ConstraintName {
+	case featureForeignKey:
+		return ErrVersionFeatureDeleted
+	case tagForeignKey:
+		return ErrVersionTagDeleted
+	default:
+		return err
+	}
 }
 
-func (r *Repo) getBannersWhere(ctx context.Context, whereQuery string, offset, limit int) ([]*entity.Banner, error) {
-	query := fmt.Sprintf(`SELECT banner.id,
-       feature_id,
-       is_active,
-       created_at,
-       updated_at,
-       title,
-       text,
-       url,
-       array_agg(bt.tag_id ORDER BY bt.tag_id) AS tag_ids
-FROM banner
-         JOIN public.content c ON c.content_id = banner.content_id
-         JOIN public.banner_tag bt ON banner.id = bt.banner_id
-%v
-GROUP BY c.content_id, banner.id, feature_id
-ORDER BY feature_id`, whereQuery)
+// mapUniqueViolation returns ErrBannerExists if err is violation of feature and tags uniqueness
+// and ErrDefaultBannerExists if feature got second default banner
+func mapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolationCode {
+		return err
+	}
 
-	if limit == math.MaxInt64 {
-		query = fmt.Sprintf(`%v OFFSET %v`, query, offset)
-	} else {
-		query = fmt.Sprintf(`%v LIMIT %v OFFSET %v`, query, limit, offset)
+	switch pgErr.ConstraintName {
+	case featureTagsUniqueIndex:
+		return ErrBannerExists
+	case featureDefaultIndex:
+		return ErrDefaultBannerExists
+	default:
+		return err
 	}
+}
 
-	type Row struct {
-		ID        int       `db:"id"`
-		FeatureID int       `db:"feature_id"`
-		TagIDsStr string    `db:"tag_ids"`
-		IsActive  bool      `db:"is_active"`
-		Title     string    `db:"title"`
-		Text      string    `db:"text"`
-		Url       string    `db:"url"`
-		CreatedAt time.Time `db:"created_at"`
-		UpdatedAt time.Time `db:"updated_at"`
+func (r *Repo) getBannersWhere(ctx context.Context, where sq.Sqlizer, offset, limit int) ([]*entity.Banner, error) {
+	return queryBannersWhere(ctx, r.DB, where, offset, limit)
+}
+
+func queryBannersWhere(ctx context.Context, q sqlx.QueryerContext, where sq.Sqlizer, offset, limit int) ([]*entity.Banner, error) {
+	builder := bannerQuery().
+		Where(where).
+		OrderBy("feature_id", "banner.id").
+		Offset(uint64(offset))
+
+	if limit != math.MaxInt64 {
+		builder = builder.Limit(uint64(limit))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
 
-		TagIDsInt []int
+	type Row struct {
+		ID          int              `db:"id"`
+		FeatureID   int              `db:"feature_id"`
+		TagIDs      pgutils.IntArray `db:"tag_ids"`
+		IsActive    bool             `db:"is_active"`
+		ActiveFrom  *time.Time       `db:"active_from"`
+		ActiveUntil *time.Time       `db:"active_until"`
+		Priority    int              `db:"priority"`
+		IsDefault   bool             `db:"is_default"`
+		Data        json.RawMessage  `db:"data"`
+		Variants    []byte           `db:"variants"`
+		Percent     int              `db:"rollout_percent"`
+		Previous    []byte           `db:"rollout_previous"`
+		CreatedAt   time.Time        `db:"created_at"`
+		UpdatedAt   time.Time        `db:"updated_at"`
 	}
 
-	rows, err := r.DB.QueryxContext(ctx, query)
+	rows, err := q.QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -108,24 +205,34 @@ ORDER BY feature_id`, whereQuery)
 			return nil, err
 		}
 
-		// row.TagIDsStr have structure {1,2,...}
-		row.TagIDsInt, err = stringutils.FillIntSliceFromString(row.TagIDsStr[1 : len(row.TagIDsStr)-1])
+		content := entity.Content{
+			Data: row.Data,
+		}
+
+		variants, err := decodeVariants(row.Variants)
 		if err != nil {
 			return nil, err
 		}
 
-		content := entity.Content{
-			Title: row.Title,
-			Text:  row.Text,
-			Url:   row.Url,
+		rollout, err := decodeRollout(row.Percent, row.Previous)
+		if err != nil {
+			return nil, err
 		}
 
 		banner := entity.Banner{
 			ID:        row.ID,
-			TagIDs:    row.TagIDsInt,
+			TagIDs:    row.TagIDs,
 			FeatureID: row.FeatureID,
 			Content:   content,
-			IsActive:  row.IsActive,
+			Variants:  variants,
+			Activity: entity.Activity{
+				IsActive:    row.IsActive,
+				ActiveFrom:  row.ActiveFrom,
+				ActiveUntil: row.ActiveUntil,
+			},
+			Priority:  row.Priority,
+			IsDefault: row.IsDefault,
+			Rollout:   rollout,
 			CreatedAt: row.CreatedAt,
 			UpdatedAt: row.UpdatedAt,
 		}
@@ -133,47 +240,104 @@ ORDER BY feature_id`, whereQuery)
 		banners = append(banners, &banner)
 	}
 
-	return banners, nil
+	// connection lost while reading rows ends iteration early, partial result must not be taken as full one
+	return banners, rows.Err()
+}
+
+// statusCondition returns SQL condition banner must satisfy to have provided status, empty status means any
+func statusCondition(status entity.BannerStatus) sq.Sqlizer {
+	switch status {
+	case entity.BannerStatusScheduled:
+		return sq.Expr("active_from > now()")
+	case entity.BannerStatusLive:
+		return sq.Expr("is_active AND (active_from IS NULL OR active_from <= now()) AND (active_until IS NULL OR active_until > now())")
+	case entity.BannerStatusExpired:
+		return sq.Expr("active_until <= now()")
+	default:
+		return sq.Expr("TRUE")
+	}
+}
+
+func (r *Repo) GetAllBanners(ctx context.Context, status entity.BannerStatus, offset, limit int) ([]*entity.Banner, error) {
+	return r.getBannersWhere(ctx, statusCondition(status), offset, limit)
 }
 
-func (r *Repo) GetAllBanners(ctx context.Context, offset, limit int) ([]*entity.Banner, error) {
-	return r.getBannersWhere(ctx, "", offset, limit)
+func (r *Repo) GetBannersWithFeatureAndTag(
+	ctx context.Context,
+	featureID, tagID int,
+	status entity.BannerStatus,
+	offset, limit int,
+) ([]*entity.Banner, error) {
+	// tag containment is checked on banner.tag_ids in the query itself, so pagination applies to matching banners only
+	return r.getBannersWhere(
+		ctx,
+		sq.And{
+			sq.Eq{"feature_id": featureID},
+			sq.Expr("banner.tag_ids @> ARRAY[?]::integer[]", tagID),
+			statusCondition(status),
+		},
+		offset, limit,
+	)
 }
 
-func (r *Repo) GetBannersWithFeatureAndTag(ctx context.Context, featureID, tagID int, offset, limit int) ([]*entity.Banner, error) {
-	banners, err := r.getBannersWhere(ctx, fmt.Sprintf("WHERE feature_id = %v", featureID), offset, limit)
+// GetBannerChanges returns banners changed and ids of banners deleted by transactions with ids at or after cursor,
+// zero cursor means all existing banners. Returned cursor is xmin of the snapshot changes are read in: transactions
+// before it are all finished and seen, so changes are read in commit order however long transactions take
+func (r *Repo) GetBannerChanges(ctx context.Context, cursor uint64) (*entity.BannerChangeSet, error) {
+	// all queries of repeatable read transaction see the same snapshot
+	tx, err := r.DB.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	var changes entity.BannerChangeSet
+
+	err = tx.GetContext(ctx, &changes.Cursor, "SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint")
 	if err != nil {
 		return nil, err
 	}
 
-	res := make([]*entity.Banner, 0, len(banners))
+	since := strconv.FormatUint(cursor, 10)
 
-	// add only banners that have tagID in their tagIDs
-	for _, banner := range banners {
-		if slices.Contains(banner.TagIDs, tagID) {
-			res = append(res, banner)
+	changes.Changed, err = queryBannersWhere(ctx, tx, sq.Expr("banner.change_xid >= ?::text::xid8", since), 0, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	// nothing is deleted from empty index
+	if cursor != 0 {
+		err = tx.SelectContext(ctx, &changes.DeletedIDs, "SELECT banner_id FROM banner_tombstone WHERE change_xid >= $1::text::xid8", since)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return res, nil
+	return &changes, tx.Commit()
 }
 
 func (r *Repo) GetBannerByID(ctx context.Context, id int) (*entity.Banner, error) {
-	query := fmt.Sprintf(`SELECT banner.id,
+	rows, err := r.DB.QueryxContext(ctx, `SELECT banner.id,
+       feature_id,
        is_active,
-       title,
-       text,
-       url,
-       array_agg(bt.tag_id ORDER BY bt.tag_id) AS tag_ids
+       active_from,
+       active_until,
+       priority,
+       is_default,
+       created_at,
+       updated_at,
+       c.content_id,
+       data,
+       variants,
+       rollout_percent,
+       rollout_previous,
+       banner.tag_ids
 FROM banner
          JOIN public.content c ON c.content_id = banner.content_id
-         JOIN public.banner_tag bt ON banner.id = bt.banner_id
-WHERE banner.id = %v
-GROUP BY banner.id, c.content_id`,
+WHERE banner.id = $1`,
 		id,
 	)
-
-	rows, err := r.DB.QueryxContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -181,123 +345,228 @@ GROUP BY banner.id, c.content_id`,
 	defer rows.Close()
 
 	type Row struct {
-		ID        int    `db:"id"`
-		IsActive  bool   `db:"is_active"`
-		Title     string `db:"title"`
-		Text      string `db:"text"`
-		Url       string `db:"url"`
-		TagIDsStr string `db:"tag_ids"`
-		TagIDsInt []int
+		ID          int              `db:"id"`
+		FeatureID   int              `db:"feature_id"`
+		IsActive    bool             `db:"is_active"`
+		ActiveFrom  *time.Time       `db:"active_from"`
+		ActiveUntil *time.Time       `db:"active_until"`
+		Priority    int              `db:"priority"`
+		IsDefault   bool             `db:"is_default"`
+		CreatedAt   time.Time        `db:"created_at"`
+		UpdatedAt   time.Time        `db:"updated_at"`
+		ContentID   int              `db:"content_id"`
+		Data        json.RawMessage  `db:"data"`
+		Variants    []byte           `db:"variants"`
+		Percent     int              `db:"rollout_percent"`
+		Previous    []byte           `db:"rollout_previous"`
+		TagIDs      pgutils.IntArray `db:"tag_ids"`
 	}
 
-	var row Row
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
 
-	if rows.Next() {
-		if err = rows.StructScan(&row); err != nil {
-			return nil, err
-		}
+	var row Row
 
-		// row.TagIDsStr have structure {1,2,...}
-		row.TagIDsInt, err = stringutils.FillIntSliceFromString(row.TagIDsStr[1 : len(row.TagIDsStr)-1])
-		if err != nil {
-			return nil, err
-		}
+	if err = rows.StructScan(&row); err != nil {
+		return nil, err
 	}
 
 	content := entity.Content{
-		Title: row.Title,
-		Text:  row.Text,
-		Url:   row.Url,
+		ID:   row.ContentID,
+		Data: row.Data,
+	}
+
+	variants, err := decodeVariants(row.Variants)
+	if err != nil {
+		return nil, err
+	}
+
+	rollout, err := decodeRollout(row.Percent, row.Previous)
+	if err != nil {
+		return nil, err
 	}
 
 	return &entity.Banner{
-			Content:  content,
-			IsActive: row.IsActive,
+			ID:        row.ID,
+			TagIDs:    row.TagIDs,
+			FeatureID: row.FeatureID,
+			Content:   content,
+			Variants:  variants,
+			Activity: entity.Activity{
+				IsActive:    row.IsActive,
+				ActiveFrom:  row.ActiveFrom,
+				ActiveUntil: row.ActiveUntil,
+			},
+			Priority:  row.Priority,
+			IsDefault: row.IsDefault,
+			Rollout:   rollout,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
 		},
 		nil
 }
 
+// GetBannerByFeatureAndTags returns banner with provided feature and exactly the provided set of tags or nil if there is no such banner.
+// Lookup is a single scan of unique index on (feature_id, tag_ids)
 func (r *Repo) GetBannerByFeatureAndTags(ctx context.Context, featureID int, tagIDs []int) (*entity.Banner, error) {
-	query := fmt.Sprintf(`SELECT banner.id,
-       is_active,
-       title,
-       text,
-       url,
-       array_agg(bt.tag_id ORDER BY bt.tag_id) AS tag_ids
-FROM banner
-         JOIN public.content c ON c.content_id = banner.content_id
-         JOIN public.banner_tag bt ON banner.id = bt.banner_id
-WHERE feature_id = %v
-GROUP BY banner.id, c.content_id
-`, featureID)
+	return r.getBannerRow(ctx, bannerQuery().
+		Where(sq.Eq{"feature_id": featureID}).
+		Where(sq.Expr("banner.tag_ids = ?", sortedTagIDs(tagIDs))),
+	)
+}
+
+// MatchBanner returns banner of the feature best matching provided tags according to matching mode, or default banner
+// of the feature if none matches, or nil if there is no default banner either. Exact mode matches banners regardless
+// of activity like GetBannerByFeatureAndTags, other modes match only live banners. Ties are broken by priority
+func (r *Repo) MatchBanner(ctx context.Context, featureID int, tagIDs []int, matching entity.BannerMatching) (*entity.Banner, error) {
+	tagIDs = sortedTagIDs(tagIDs)
+
+	var (
+		matches sq.Sqlizer
+		rank    []sq.Sqlizer
+	)
+
+	switch matching {
+	case entity.BannerMatchingBestSubset:
+		matches = sq.Expr("banner.tag_ids <@ ?::integer[]", tagIDs)
+		rank = []sq.Sqlizer{sq.Expr("cardinality(banner.tag_ids) DESC"), sq.Expr("priority DESC")}
+	case entity.BannerMatchingAnyTag:
+		matches = sq.Expr("banner.tag_ids && ?::integer[]", tagIDs)
+		rank = []sq.Sqlizer{
+			sq.Expr("priority DESC"),
+			sq.Expr("(SELECT count(*) FROM unnest(banner.tag_ids) AS tag_id WHERE tag_id = ANY (?::integer[])) DESC", tagIDs),
+		}
+	default:
+		// both lookups are single row ones served by unique indexes
+		banner, err := r.GetBannerByFeatureAndTags(ctx, featureID, tagIDs)
+		if err != nil || banner != nil {
+			return banner, err
+		}
+
+		return r.getBannerRow(ctx, bannerQuery().Where(sq.Eq{"feature_id": featureID}).Where(sq.Expr("is_default")))
+	}
+
+	live := sq.And{statusCondition(entity.BannerStatusLive), matches}
 
-	dbRows, err := r.DB.QueryxContext(ctx, query)
+	// default banner is a candidate too, but loses to any matching one
+	builder := bannerQuery().
+		Where(sq.Eq{"feature_id": featureID}).
+		Where(sq.Or{live, sq.Expr("is_default")}).
+		OrderByClause(sq.Expr("? DESC", live))
+
+	for _, clause := range rank {
+		builder = builder.OrderByClause(clause)
+	}
+
+	return r.getBannerRow(ctx, builder.OrderBy("banner.id").Limit(1))
+}
+
+// bannerQuery selects all columns of banners along with their content
+func bannerQuery() sq.SelectBuilder {
+	return psql.Select(
+		"banner.id",
+		"feature_id",
+		"is_active",
+		"active_from",
+		"active_until",
+		"priority",
+		"is_default",
+		"created_at",
+		"updated_at",
+		"data",
+		"variants",
+		"rollout_percent",
+		"rollout_previous",
+		"banner.tag_ids",
+	).
+		From("banner").
+		Join("public.content c ON c.content_id = banner.content_id")
+}
+
+// getBannerRow returns the first banner selected by builder or nil if nothing is selected.
+// Connectivity errors are reported as unavailability, so callers can serve banner from elsewhere
+func (r *Repo) getBannerRow(ctx context.Context, builder sq.SelectBuilder) (*entity.Banner, error) {
+	query, args, err := builder.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	defer dbRows.Close()
-
 	type Row struct {
-		ID        int    `db:"id"`
-		IsActive  bool   `db:"is_active"`
-		Title     string `db:"title"`
-		Text      string `db:"text"`
-		Url       string `db:"url"`
-		TagIDsStr string `db:"tag_ids"`
-		TagIDsInt []int
+		ID          int              `db:"id"`
+		FeatureID   int              `db:"feature_id"`
+		IsActive    bool             `db:"is_active"`
+		ActiveFrom  *time.Time       `db:"active_from"`
+		ActiveUntil *time.Time       `db:"active_until"`
+		Priority    int              `db:"priority"`
+		IsDefault   bool             `db:"is_default"`
+		CreatedAt   time.Time        `db:"created_at"`
+		UpdatedAt   time.Time        `db:"updated_at"`
+		Data        json.RawMessage  `db:"data"`
+		Variants    []byte           `db:"variants"`
+		Percent     int              `db:"rollout_percent"`
+		Previous    []byte           `db:"rollout_previous"`
+		TagIDs      pgutils.IntArray `db:"tag_ids"`
 	}
 
-	var rows []*Row
-
-	for dbRows.Next() {
-		var row Row
+	var row Row
 
-		if err = dbRows.StructScan(&row); err != nil {
-			return nil, err
-		}
+	err = r.DB.QueryRowxContext(ctx, query, args...).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 
-		// row.TagIDs have structure {1,2,...}
-		row.TagIDsInt, err = stringutils.FillIntSliceFromString(row.TagIDsStr[1 : len(row.TagIDsStr)-1])
-		if err != nil {
-			return nil, err
-		}
+	if pgutils.IsConnectivityError(err) {
+		return nil, errs.Wrap(errs.ErrUnavailable, err)
+	}
 
-		rows = append(rows, &row)
+	if err != nil {
+		return nil, err
 	}
 
-	// each row represents banner with banner.feature_id = featureID => find banner with banner.tag_ids = tagIDs
-	for _, row := range rows {
-		if sliceutils.Equals(row.TagIDsInt, tagIDs) { // here tagIDs gotta be sorted by asc, row.TagIDs already sorted
-			content := entity.Content{
-				Title: row.Title,
-				Text:  row.Text,
-				Url:   row.Url,
-			}
+	variants, err := decodeVariants(row.Variants)
+	if err != nil {
+		return nil, err
+	}
 
-			return &entity.Banner{
-					Content:  content,
-					IsActive: row.IsActive,
-				},
-				nil
-		}
+	rollout, err := decodeRollout(row.Percent, row.Previous)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, nil
+	return &entity.Banner{
+			ID:        row.ID,
+			TagIDs:    row.TagIDs,
+			FeatureID: row.FeatureID,
+			Content: entity.Content{
+				Data: row.Data,
+			},
+			Variants: variants,
+			Activity: entity.Activity{
+				IsActive:    row.IsActive,
+				ActiveFrom:  row.ActiveFrom,
+				ActiveUntil: row.ActiveUntil,
+			},
+			Priority:  row.Priority,
+			IsDefault: row.IsDefault,
+			Rollout:   rollout,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		},
+		nil
 }
 
 func (r *Repo) CreateBanner(ctx context.Context, banner entity.Banner) (*entity.Banner, error) {
 	// execute in transaction
 	tx, err := r.DB.BeginTxx(ctx, &sql.TxOptions{})
-
-	defer tx.Rollback()
-
 	if err != nil {
 		return nil, err
 	}
 
+	defer tx.Rollback()
+
 	// firstly add content to Content table
-	rows, err := tx.NamedQuery(`INSERT INTO content (title, text, url) VALUES (:title, :text, :url) RETURNING *`, &banner.Content)
+	rows, err := tx.NamedQuery(`INSERT INTO content (data) VALUES (:data) RETURNING *`, &banner.Content)
 	if err != nil {
 		return nil, err
 	}
@@ -310,39 +579,55 @@ func (r *Repo) CreateBanner(ctx context.Context, banner entity.Banner) (*entity.
 		}
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
 	// close rows
 	if err = rows.Close(); err != nil {
 		return nil, err
 	}
 
-	// then insert new banner into banner table
-	query := fmt.Sprintf(`INSERT INTO banner (feature_id,is_active, content_id) 
-VALUES (:feature_id, :is_active, %v) 
-RETURNING id, feature_id, is_active, created_at, updated_at`,
-		content.ID)
+	banner.TagIDs = sortedTagIDs(banner.TagIDs)
 
-	rows, err = tx.NamedQuery(query, &banner)
+	variants, err := encodeVariants(banner.Variants)
 	if err != nil {
 		return nil, err
 	}
 
-	if rows.Next() {
-		if err = rows.StructScan(&banner); err != nil {
-			return nil, err
-		}
+	rolloutPercent, rolloutPrevious, err := encodeRollout(banner.Rollout)
+	if err != nil {
+		return nil, err
 	}
 
-	// close rows
-	if err = rows.Close(); err != nil {
+	// then insert new banner into banner table
+	query, args, err := psql.Insert("banner").
+		Columns(
+			"feature_id", "tag_ids", "is_active", "active_from", "active_until", "priority", "is_default", "variants",
+			"rollout_percent", "rollout_previous", "content_id",
+		).
+		Values(
+			banner.FeatureID, banner.TagIDs, banner.IsActive, banner.ActiveFrom, banner.ActiveUntil,
+			banner.Priority, banner.IsDefault, variants, rolloutPercent, rolloutPrevious, content.ID,
+		).
+		Suffix("RETURNING id, feature_id, is_active, active_from, active_until, priority, is_default, created_at, updated_at").
+		ToSql()
+	if err != nil {
 		return nil, err
 	}
 
+	if err = tx.QueryRowxContext(ctx, query, args...).StructScan(&banner); err != nil {
+		return nil, mapUniqueViolation(err)
+	}
+
 	// for each tag id in entity.banner create new row (banner.ID, tag.ID) in BannerTag table
-	for _, tag := range banner.TagIDs {
-		_, err = tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO banner_tag (banner_id, tag_id) VALUES (%v, %v)", banner.ID, tag))
-		if err != nil {
-			return nil, err
-		}
+	if err = insertBannerTags(ctx, tx, banner.ID, banner.TagIDs); err != nil {
+		return nil, err
+	}
+
+	// save created banner as its first version
+	if err = createVersion(ctx, tx, banner.ID, banner.UpdatedBy); err != nil {
+		return nil, err
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -354,52 +639,90 @@ RETURNING id, feature_id, is_active, created_at, updated_at`,
 	return &banner, nil
 }
 
-func (r *Repo) UpdateBanner(ctx context.Context, id int, updateModel entity.Banner) error {
+// UpdateBanner applies partial update to the banner and saves the result as new version
+func (r *Repo) UpdateBanner(ctx context.Context, id int, update entity.BannerUpdate) error {
 	tx, err := r.DB.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
 
 	defer tx.Rollback()
 
-	if err != nil {
-		return err
+	// update provided fields in banner table
+	builder := psql.Update("banner").Set("updated_at", sq.Expr("now()"))
+
+	if update.IsActive != nil {
+		builder = builder.Set("is_active", *update.IsActive)
 	}
 
-	// update some fields in banner table
-	setQuery := fmt.Sprintf("is_active = %v, updated_at = now()", updateModel.IsActive)
+	if update.Priority != nil {
+		builder = builder.Set("priority", *update.Priority)
+	}
 
-	if updateModel.FeatureID != 0 {
-		setQuery += fmt.Sprintf(", feature_id = %v", updateModel.FeatureID)
+	if update.IsDefault != nil {
+		builder = builder.Set("is_default", *update.IsDefault)
 	}
 
-	rows, err := tx.QueryxContext(
-		ctx,
-		fmt.Sprintf("UPDATE banner SET %v WHERE id = %v RETURNING content_id", setQuery, id),
-	)
-	if err != nil {
-		return err
+	// bounds of activation window are kept if omitted, bound with nil time is removed
+	if update.ActiveFrom != nil {
+		builder = builder.Set("active_from", update.ActiveFrom.At)
 	}
 
-	contentIdStruct := struct {
-		ContentID int `db:"content_id"`
-	}{}
+	if update.ActiveUntil != nil {
+		builder = builder.Set("active_until", update.ActiveUntil.At)
+	}
 
-	// fetch content id
-	if rows.Next() {
-		if err = rows.StructScan(&contentIdStruct); err != nil {
+	if update.FeatureID != 0 {
+		builder = builder.Set("feature_id", update.FeatureID)
+	}
+
+	if len(update.TagIDs) != 0 {
+		builder = builder.Set("tag_ids", sortedTagIDs(update.TagIDs))
+	}
+
+	// nil variants are kept, empty ones are removed
+	if update.Variants != nil {
+		variants, err := encodeVariants(update.Variants)
+		if err != nil {
 			return err
 		}
+
+		builder = builder.Set("variants", variants)
 	}
 
-	// close rows
-	if err = rows.Close(); err != nil {
+	// nil rollout is kept, previous content is replaced along with the percent
+	if update.Rollout != nil {
+		rolloutPercent, rolloutPrevious, err := encodeRollout(update.Rollout)
+		if err != nil {
+			return err
+		}
+
+		builder = builder.Set("rollout_percent", rolloutPercent).Set("rollout_previous", rolloutPrevious)
+	}
+
+	query, args, err := builder.
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING content_id").
+		ToSql()
+	if err != nil {
 		return err
 	}
 
-	// update content associated with this banner
-	setQuery = setQueryForUpdateModel(updateModel)
+	// fetch content id
+	var contentID int
+
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(&contentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNoSuchBanner
+	}
 
-	// execute query only if updating something
-	if setQuery != "" {
-		_, err = tx.QueryxContext(ctx, fmt.Sprintf(`UPDATE content SET %v WHERE content_id = %v`, setQuery, contentIdStruct.ContentID))
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+
+	// replace content associated with this banner if new one provided
+	if len(update.Content) != 0 {
+		_, err = tx.ExecContext(ctx, `UPDATE content SET data = $1 WHERE content_id = $2`, update.Content, contentID)
 		if err != nil {
 			return err
 		}
@@ -407,10 +730,10 @@ func (r *Repo) UpdateBanner(ctx context.Context, id int, updateModel entity.Bann
 
 	/* update tag ids in banner_tag table:
 	to do this we need firstly delete all rows from banner_tag where banner_id = id,
-	then add new rows in this table of form (banner_id = id, tag_id = updateModel.tagIds[i])
+	then add new rows in this table of form (banner_id = id, tag_id = update.TagIDs[i])
 	*/
-	if len(updateModel.TagIDs) != 0 {
-		_, err = tx.QueryxContext(
+	if len(update.TagIDs) != 0 {
+		_, err = tx.ExecContext(
 			ctx,
 			"DELETE FROM banner_tag WHERE banner_id = $1",
 			id,
@@ -419,32 +742,356 @@ func (r *Repo) UpdateBanner(ctx context.Context, id int, updateModel entity.Bann
 			return err
 		}
 
-		for _, tag := range updateModel.TagIDs {
-			_, err = tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO banner_tag (banner_id, tag_id) VALUES (%v, %v)", id, tag))
-			if err != nil {
-				return err
-			}
+		if err = insertBannerTags(ctx, tx, id, sortedTagIDs(update.TagIDs)); err != nil {
+			return err
 		}
 	}
 
+	// save updated banner as new version
+	if err = createVersion(ctx, tx, id, update.UpdatedBy); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
 func (r *Repo) DeleteBanner(ctx context.Context, id int) (*entity.Banner, error) {
-	rows, err := r.DB.QueryxContext(ctx, "DELETE FROM banner WHERE id = $1 RETURNING *", id)
+	type Row struct {
+		entity.Banner
+		TagIDs pgutils.IntArray `db:"tag_ids"`
+	}
+
+	var row Row
+
+	err := r.DB.QueryRowxContext(ctx, `DELETE
+FROM banner
+WHERE id = $1
+RETURNING id, feature_id, tag_ids, content_id, is_active, active_from, active_until, priority, is_default, created_at, updated_at`, id).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoSuchBanner
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	row.Banner.TagIDs = row.TagIDs
+
+	return &row.Banner, nil
+}
+
+// insertBannerTags creates rows (bannerID, tagID) in banner_tag table for each of provided tags
+func insertBannerTags(ctx context.Context, tx *sqlx.Tx, bannerID int, tagIDs []int) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	builder := psql.Insert("banner_tag").Columns("banner_id", "tag_id")
+	for _, tagID := range tagIDs {
+		builder = builder.Values(bannerID, tagID)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// createVersion saves current state of the banner as its next version, createdBy = 0 means unknown author.
+// Banner row is locked first, so concurrent changes of the banner get distinct version numbers
+func createVersion(ctx context.Context, tx *sqlx.Tx, bannerID, createdBy int) error {
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM banner WHERE id = $1 FOR UPDATE`, bannerID); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `INSERT INTO banner_version (banner_id, version, feature_id, tag_ids, content, variants, is_active, active_from, active_until,
+                            priority, is_default, rollout_percent, rollout_previous, created_by)
+SELECT banner.id,
+       COALESCE((SELECT MAX(version) FROM banner_version WHERE banner_id = banner.id), 0) + 1,
+       feature_id,
+       banner.tag_ids,
+       data,
+       variants,
+       is_active,
+       active_from,
+       active_until,
+       priority,
+       is_default,
+       rollout_percent,
+       rollout_previous,
+       NULLIF($2, 0)
+FROM banner
+         JOIN public.content c ON c.content_id = banner.content_id
+WHERE banner.id = $1`,
+		bannerID, createdBy,
+	)
+
+	return err
+}
+
+// bannerVersionQuery selects all columns of versions of the banner
+const bannerVersionQuery = `SELECT id,
+       banner_id,
+       version,
+       feature_id,
+       tag_ids,
+       content AS data,
+       variants,
+       is_active,
+       active_from,
+       active_until,
+       priority,
+       is_default,
+       rollout_percent,
+       rollout_previous,
+       COALESCE(created_by, 0) AS created_by,
+       created_at
+FROM banner_version
+WHERE banner_id = $1`
+
+func (r *Repo) GetBannerVersions(ctx context.Context, bannerID int, offset, limit int) ([]*entity.BannerVersion, error) {
+	query := bannerVersionQuery + `
+ORDER BY version DESC`
+
+	args := []any{bannerID}
+
+	if limit == math.MaxInt64 {
+		query += ` OFFSET $2`
+		args = append(args, offset)
+	} else {
+		query += ` LIMIT $2 OFFSET $3`
+		args = append(args, limit, offset)
+	}
+
+	return r.getBannerVersions(ctx, query, args...)
+}
+
+// GetBannerVersion returns provided version of the banner or ErrNoSuchBannerVersion if there is no such version
+func (r *Repo) GetBannerVersion(ctx context.Context, bannerID, version int) (*entity.BannerVersion, error) {
+	versions, err := r.getBannerVersions(ctx, bannerVersionQuery+`
+  AND version = $2`, bannerID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, ErrNoSuchBannerVersion
+	}
+
+	return versions[0], nil
+}
+
+func (r *Repo) getBannerVersions(ctx context.Context, query string, args ...any) ([]*entity.BannerVersion, error) {
+	rows, err := r.DB.QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 
 	defer rows.Close()
 
-	var banner entity.Banner
+	type Row struct {
+		ID          int              `db:"id"`
+		BannerID    int              `db:"banner_id"`
+		Version     int              `db:"version"`
+		FeatureID   int              `db:"feature_id"`
+		TagIDs      pgutils.IntArray `db:"tag_ids"`
+		Data        json.RawMessage  `db:"data"`
+		Variants    []byte           `db:"variants"`
+		IsActive    bool             `db:"is_active"`
+		ActiveFrom  *time.Time       `db:"active_from"`
+		ActiveUntil *time.Time       `db:"active_until"`
+		Priority    int              `db:"priority"`
+		IsDefault   bool             `db:"is_default"`
+		Percent     int              `db:"rollout_percent"`
+		Previous    []byte           `db:"rollout_previous"`
+		CreatedBy   int              `db:"created_by"`
+		CreatedAt   time.Time        `db:"created_at"`
+	}
 
-	if rows.Next() {
-		if err = rows.StructScan(&banner); err != nil {
+	var versions []*entity.BannerVersion
+
+	for rows.Next() {
+		var row Row
+
+		if err = rows.StructScan(&row); err != nil {
 			return nil, err
 		}
+
+		variants, err := decodeVariants(row.Variants)
+		if err != nil {
+			return nil, err
+		}
+
+		rollout, err := decodeRollout(row.Percent, row.Previous)
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, &entity.BannerVersion{
+			ID:        row.ID,
+			BannerID:  row.BannerID,
+			Version:   row.Version,
+			TagIDs:    row.TagIDs,
+			FeatureID: row.FeatureID,
+			Content: entity.Content{
+				Data: row.Data,
+			},
+			Variants: variants,
+			Activity: entity.Activity{
+				IsActive:    row.IsActive,
+				ActiveFrom:  row.ActiveFrom,
+				ActiveUntil: row.ActiveUntil,
+			},
+			Priority:  row.Priority,
+			IsDefault: row.IsDefault,
+			Rollout:   rollout,
+			CreatedBy: row.CreatedBy,
+			CreatedAt: row.CreatedAt,
+		})
 	}
 
-	return &banner, nil
+	return versions, rows.Err()
+}
+
+// RestoreBannerVersion makes provided version of the banner current, restoring is saved as new version
+func (r *Repo) RestoreBannerVersion(ctx context.Context, bannerID, version, restoredBy int) error {
+	tx, err := r.DB.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	type Row struct {
+		FeatureID   int              `db:"feature_id"`
+		TagIDs      pgutils.IntArray `db:"tag_ids"`
+		Data        json.RawMessage  `db:"data"`
+		Variants    []byte           `db:"variants"`
+		IsActive    bool             `db:"is_active"`
+		ActiveFrom  *time.Time       `db:"active_from"`
+		ActiveUntil *time.Time       `db:"active_until"`
+		Priority    int              `db:"priority"`
+		IsDefault   bool             `db:"is_default"`
+		Percent     int              `db:"rollout_percent"`
+		Previous    []byte           `db:"rollout_previous"`
+	}
+
+	var row Row
+
+	err = tx.QueryRowxContext(
+		ctx,
+		`SELECT feature_id, tag_ids, content AS data, variants, is_active, active_from, active_until,
+       priority, is_default, rollout_percent, rollout_previous
+FROM banner_version
+WHERE banner_id = $1
+  AND version = $2`,
+		bannerID, version,
+	).StructScan(&row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNoSuchBannerVersion
+	}
+
+	if err != nil {
+		return err
+	}
+
+	decodedVariants, err := decodeVariants(row.Variants)
+	if err != nil {
+		return err
+	}
+
+	variants, err := encodeVariants(decodedVariants)
+	if err != nil {
+		return err
+	}
+
+	decodedRollout, err := decodeRollout(row.Percent, row.Previous)
+	if err != nil {
+		return err
+	}
+
+	rolloutPercent, rolloutPrevious, err := encodeRollout(decodedRollout)
+	if err != nil {
+		return err
+	}
+
+	var contentID int
+
+	err = tx.QueryRowxContext(
+		ctx,
+		`UPDATE banner
+SET feature_id       = $1,
+    tag_ids          = $2,
+    is_active        = $3,
+    active_from      = $4,
+    active_until     = $5,
+    variants         = $6,
+    priority         = $7,
+    is_default       = $8,
+    rollout_percent  = $9,
+    rollout_previous = $10,
+    updated_at       = now()
+WHERE id = $11
+RETURNING content_id`,
+		row.FeatureID, sortedTagIDs(row.TagIDs), row.IsActive, row.ActiveFrom, row.ActiveUntil, variants,
+		row.Priority, row.IsDefault, rolloutPercent, rolloutPrevious, bannerID,
+	).Scan(&contentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNoSuchBanner
+	}
+
+	if err != nil {
+		return mapVersionReferenceViolation(mapUniqueViolation(err))
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE content SET data = $1 WHERE content_id = $2",
+		row.Data, contentID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM banner_tag WHERE banner_id = $1", bannerID); err != nil {
+		return err
+	}
+
+	if err = insertBannerTags(ctx, tx, bannerID, sortedTagIDs(row.TagIDs)); err != nil {
+		return mapVersionReferenceViolation(err)
+	}
+
+	if err = createVersion(ctx, tx, bannerID, restoredBy); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteBanners deletes banners with provided feature and tag along with their content, zero featureID or tagID means any.
+// Returns number of deleted banners
+func (r *Repo) DeleteBanners(ctx context.Context, featureID, tagID int) (int, error) {
+	// deleting content cascades to banner, banner_tag and banner_version tables
+	res, err := r.DB.ExecContext(ctx, `DELETE
+FROM content
+WHERE content_id IN (SELECT banner.content_id
+                     FROM banner
+                     WHERE ($1 = 0 OR feature_id = $1)
+                       AND ($2 = 0 OR tag_ids @> ARRAY[$2]::integer[]))`,
+		featureID, tagID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(deleted), nil
 }
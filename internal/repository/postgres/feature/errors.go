@@ -0,0 +1,7 @@
+package feature
+
+import "avito-backend-trainee-2024/pkg/errs"
+
+var (
+	ErrFeatureHasBanners = errs.New(errs.ErrConflict, "feature has banners, delete them first or use cascade deletion")
+)
@@ -2,12 +2,20 @@ package feature
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 
 	"avito-backend-trainee-2024/internal/domain/entity"
 )
 
+const (
+	foreignKeyViolationCode = "23503"
+)
+
 type Repo struct {
 	DB *sqlx.DB
 }
@@ -33,3 +41,127 @@ func (r *Repo) GetFeatureByID(ctx context.Context, id int) (*entity.Feature, err
 
 	return &feature, nil
 }
+
+func (r *Repo) UpdateFeatureContentSchema(ctx context.Context, id int, schema json.RawMessage) (*entity.Feature, error) {
+	row := r.DB.QueryRowxContext(
+		ctx,
+		"UPDATE feature SET content_schema = $1, updated_at = now() WHERE id = $2 RETURNING *",
+		schema, id,
+	)
+
+	if err := row.Err(); err != nil {
+		return nil, err
+	}
+
+	var feature entity.Feature
+
+	if err := row.StructScan(&feature); err != nil {
+		return nil, err
+	}
+
+	return &feature, nil
+}
+
+func (r *Repo) GetFeatures(ctx context.Context, offset, limit int) ([]*entity.Feature, error) {
+	rows, err := r.DB.QueryxContext(ctx, "SELECT * FROM feature ORDER BY id LIMIT $1 OFFSET $2", limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	features := make([]*entity.Feature, 0)
+
+	for rows.Next() {
+		var feature entity.Feature
+
+		if err = rows.StructScan(&feature); err != nil {
+			return nil, err
+		}
+
+		features = append(features, &feature)
+	}
+
+	return features, rows.Err()
+}
+
+func (r *Repo) CreateFeature(ctx context.Context, feature entity.Feature) (*entity.Feature, error) {
+	row := r.DB.QueryRowxContext(
+		ctx,
+		"INSERT INTO feature (name, content_schema) VALUES ($1, COALESCE($2, '{}'::jsonb)) RETURNING *",
+		feature.Name, feature.ContentSchema,
+	)
+
+	if err := row.Err(); err != nil {
+		return nil, err
+	}
+
+	var created entity.Feature
+
+	if err := row.StructScan(&created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) UpdateFeatureName(ctx context.Context, id int, name string) (*entity.Feature, error) {
+	row := r.DB.QueryRowxContext(
+		ctx,
+		"UPDATE feature SET name = $1, updated_at = now() WHERE id = $2 RETURNING *",
+		name, id,
+	)
+
+	if err := row.Err(); err != nil {
+		return nil, err
+	}
+
+	var feature entity.Feature
+
+	if err := row.StructScan(&feature); err != nil {
+		return nil, err
+	}
+
+	return &feature, nil
+}
+
+// DeleteFeature deletes the feature, feature having banners is deleted only with cascade along with its banners
+func (r *Repo) DeleteFeature(ctx context.Context, id int, cascade bool) (*entity.Feature, error) {
+	tx, err := r.DB.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	if cascade {
+		// deleting content cascades to banner, banner_tag and banner_version tables
+		_, err = tx.ExecContext(
+			ctx,
+			"DELETE FROM content WHERE content_id IN (SELECT content_id FROM banner WHERE feature_id = $1)",
+			id,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var feature entity.Feature
+
+	err = tx.QueryRowxContext(ctx, "DELETE FROM feature WHERE id = $1 RETURNING *", id).StructScan(&feature)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+		return nil, ErrFeatureHasBanners
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &feature, nil
+}
@@ -0,0 +1,7 @@
+package job
+
+import "avito-backend-trainee-2024/pkg/errs"
+
+var (
+	ErrNoSuchJob = errs.New(errs.ErrNotFound, "no such job")
+)
@@ -0,0 +1,106 @@
+package job
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+)
+
+const selectColumns = `id, COALESCE(feature_id, 0) AS feature_id, COALESCE(tag_id, 0) AS tag_id, status, deleted, error, created_at, updated_at`
+
+type Repo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+func (r *Repo) CreateJob(ctx context.Context, job entity.Job) (*entity.Job, error) {
+	var created entity.Job
+
+	err := r.DB.QueryRowxContext(
+		ctx,
+		`INSERT INTO job (feature_id, tag_id) VALUES (NULLIF($1, 0), NULLIF($2, 0)) RETURNING `+selectColumns,
+		job.FeatureID, job.TagID,
+	).StructScan(&created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) GetJobByID(ctx context.Context, id int) (*entity.Job, error) {
+	var job entity.Job
+
+	err := r.DB.QueryRowxContext(ctx, `SELECT `+selectColumns+` FROM job WHERE id = $1`, id).StructScan(&job)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoSuchJob
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// ClaimPendingJob marks the oldest pending job as running and returns it, returns nil if there are no pending jobs.
+// Running jobs whose lease wasn't extended for leaseTimeout are claimed again, they were left by crashed instances.
+// Zero leaseTimeout disables reclaiming. Jobs locked by other instances are skipped, so each job is run only once
+func (r *Repo) ClaimPendingJob(ctx context.Context, leaseTimeout time.Duration) (*entity.Job, error) {
+	var job entity.Job
+
+	err := r.DB.QueryRowxContext(ctx, `UPDATE job
+SET status     = $1,
+    updated_at = now()
+WHERE id = (SELECT id
+            FROM job
+            WHERE status = $2
+               OR ($3::double precision > 0
+                AND status = $1
+                AND updated_at < now() - make_interval(secs => $3::double precision))
+            ORDER BY id
+            LIMIT 1 FOR UPDATE SKIP LOCKED)
+RETURNING `+selectColumns,
+		entity.JobStatusRunning, entity.JobStatusPending, leaseTimeout.Seconds(),
+	).StructScan(&job)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// ExtendJobLease marks running job as alive, so other instances don't claim it again
+func (r *Repo) ExtendJobLease(ctx context.Context, id int) error {
+	_, err := r.DB.ExecContext(
+		ctx,
+		"UPDATE job SET updated_at = now() WHERE id = $1 AND status = $2",
+		id, entity.JobStatusRunning,
+	)
+
+	return err
+}
+
+func (r *Repo) FinishJob(ctx context.Context, id int, status entity.JobStatus, deleted int, errMsg string) error {
+	_, err := r.DB.ExecContext(
+		ctx,
+		"UPDATE job SET status = $1, deleted = $2, error = $3, updated_at = now() WHERE id = $4",
+		status, deleted, errMsg, id,
+	)
+
+	return err
+}
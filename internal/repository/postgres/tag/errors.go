@@ -0,0 +1,7 @@
+package tag
+
+import "avito-backend-trainee-2024/pkg/errs"
+
+var (
+	ErrTagHasBanners = errs.New(errs.ErrConflict, "tag has banners, delete them first or use cascade deletion")
+)
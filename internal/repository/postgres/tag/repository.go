@@ -2,14 +2,23 @@ package tag
 
 import (
 	"context"
-	"fmt"
-	"strconv"
+	"database/sql"
+	"errors"
 
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 
 	"avito-backend-trainee-2024/internal/domain/entity"
 )
 
+const (
+	foreignKeyViolationCode = "23503"
+)
+
+// psql builds queries with postgres placeholders, all values are passed to database as bound arguments
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
 type Repo struct {
 	DB *sqlx.DB
 }
@@ -21,17 +30,16 @@ func New(db *sqlx.DB) *Repo {
 }
 
 func (r *Repo) GetTagsWithIDs(ctx context.Context, IDs []int) ([]*entity.Tag, error) {
-	idsStr := ""
-
-	for i, id := range IDs {
-		idsStr += strconv.Itoa(id)
-
-		if i != len(IDs)-1 {
-			idsStr += ","
-		}
+	query, args, err := psql.Select("*").
+		From("tag").
+		Where(sq.Eq{"id": IDs}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, err
 	}
 
-	rows, err := r.DB.QueryxContext(ctx, fmt.Sprintf("SELECT * FROM tag WHERE id in (%v) ORDER BY id", idsStr))
+	rows, err := r.DB.QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -50,11 +58,11 @@ func (r *Repo) GetTagsWithIDs(ctx context.Context, IDs []int) ([]*entity.Tag, er
 		tags = append(tags, &tag)
 	}
 
-	return tags, nil
+	return tags, rows.Err()
 }
 
 func (r *Repo) GetTagByID(ctx context.Context, id int) (*entity.Tag, error) {
-	rows, err := r.DB.QueryxContext(ctx, fmt.Sprintf("SELECT * FROM tag WHERE id = %v", id))
+	rows, err := r.DB.QueryxContext(ctx, "SELECT * FROM tag WHERE id = $1", id)
 	if err != nil {
 		return nil, err
 	}
@@ -69,5 +77,124 @@ func (r *Repo) GetTagByID(ctx context.Context, id int) (*entity.Tag, error) {
 		}
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (r *Repo) GetTags(ctx context.Context, offset, limit int) ([]*entity.Tag, error) {
+	rows, err := r.DB.QueryxContext(ctx, "SELECT * FROM tag ORDER BY id LIMIT $1 OFFSET $2", limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	tags := make([]*entity.Tag, 0)
+
+	for rows.Next() {
+		var tag entity.Tag
+
+		if err = rows.StructScan(&tag); err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, &tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// CreateTags creates tags with provided names, either all of them or none
+func (r *Repo) CreateTags(ctx context.Context, names []string) ([]*entity.Tag, error) {
+	tx, err := r.DB.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	tags := make([]*entity.Tag, 0, len(names))
+
+	for _, name := range names {
+		var tag entity.Tag
+
+		if err = tx.QueryRowxContext(ctx, "INSERT INTO tag (name) VALUES ($1) RETURNING *", name).StructScan(&tag); err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, &tag)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *Repo) UpdateTagName(ctx context.Context, id int, name string) (*entity.Tag, error) {
+	row := r.DB.QueryRowxContext(
+		ctx,
+		"UPDATE tag SET name = $1, updated_at = now() WHERE id = $2 RETURNING *",
+		name, id,
+	)
+
+	if err := row.Err(); err != nil {
+		return nil, err
+	}
+
+	var tag entity.Tag
+
+	if err := row.StructScan(&tag); err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// DeleteTag deletes the tag, tag of some banners is deleted only with cascade along with these banners
+func (r *Repo) DeleteTag(ctx context.Context, id int, cascade bool) (*entity.Tag, error) {
+	tx, err := r.DB.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	if cascade {
+		// deleting content cascades to banner, banner_tag and banner_version tables
+		_, err = tx.ExecContext(ctx, `DELETE
+FROM content
+WHERE content_id IN (SELECT banner.content_id
+                     FROM banner
+                              JOIN banner_tag bt ON banner.id = bt.banner_id
+                     WHERE bt.tag_id = $1)`,
+			id,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var tag entity.Tag
+
+	err = tx.QueryRowxContext(ctx, "DELETE FROM tag WHERE id = $1 RETURNING *", id).StructScan(&tag)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+		return nil, ErrTagHasBanners
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return &tag, nil
 }
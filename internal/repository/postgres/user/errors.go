@@ -1,7 +1,7 @@
 package user
 
-import "errors"
+import "avito-backend-trainee-2024/pkg/errs"
 
 var (
-	ErrUsernameExists = errors.New("user with this username already exists")
+	ErrUsernameExists = errs.New(errs.ErrConflict, "user with this username already exists")
 )
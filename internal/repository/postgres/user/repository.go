@@ -20,12 +20,15 @@ func New(db *sqlx.DB) *Repo {
 
 func (r *Repo) CreateUser(ctx context.Context, user entity.User) (*entity.User, error) {
 	rows, err := r.DB.NamedQueryContext(ctx,
-		`INSERT INTO users (username, is_admin, hashed_password) VALUES (:username, :is_admin, :hashed_password) RETURNING *`,
+		`INSERT INTO users (username, is_admin, always_fresh, hashed_password)
+		VALUES (:username, :is_admin, :always_fresh, :hashed_password) RETURNING *`,
 		&user)
 	if err != nil {
 		return nil, err
 	}
 
+	defer rows.Close()
+
 	var created entity.User
 
 	if rows.Next() {
@@ -34,7 +37,9 @@ func (r *Repo) CreateUser(ctx context.Context, user entity.User) (*entity.User,
 		}
 	}
 
-	defer rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return &created, nil
 }
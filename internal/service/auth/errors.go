@@ -0,0 +1,7 @@
+package auth
+
+import "avito-backend-trainee-2024/pkg/errs"
+
+var (
+	ErrInvalidCredentials = errs.New(errs.ErrUnauthorized, "invalid username or password")
+)
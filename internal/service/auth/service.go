@@ -2,6 +2,8 @@ package auth
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"golang.org/x/crypto/bcrypt"
 
@@ -51,11 +53,19 @@ func (s *Service) RegisterUser(ctx context.Context, user entity.User) (*entity.U
 
 func (s *Service) Login(ctx context.Context, username, password string) (*entity.User, error) {
 	user, err := s.UserRepo.GetUserByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidCredentials
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
 	err = s.Hasher.CompareHashAndPassword([]byte(user.HashedPassword), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return nil, ErrInvalidCredentials
+	}
+
 	if err != nil {
 		return nil, err
 	}
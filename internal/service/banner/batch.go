@@ -0,0 +1,51 @@
+package banner
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// batchLookupConcurrency limits number of banners of one batch looked up at the same time
+const batchLookupConcurrency = 8
+
+// BatchLookup is a result of lookup of the banner of one feature of a batch
+type BatchLookup struct {
+	FeatureID int
+	Lookup    *BannerLookup
+	Err       error
+}
+
+// LookupBanners looks up banners of several features for the same tags the same way LookupBanner does.
+// Lookups run concurrently, errors are reported per feature, results follow order of unique feature ids
+func (s *Service) LookupBanners(ctx context.Context, featureIDs []int, tagIDs []int, useLastRevision bool) []BatchLookup {
+	results := make([]BatchLookup, 0, len(featureIDs))
+	seen := make(map[int]struct{}, len(featureIDs))
+
+	for _, featureID := range featureIDs {
+		if _, ok := seen[featureID]; ok {
+			continue
+		}
+
+		seen[featureID] = struct{}{}
+		results = append(results, BatchLookup{FeatureID: featureID})
+	}
+
+	var group errgroup.Group
+
+	group.SetLimit(batchLookupConcurrency)
+
+	for i := range results {
+		result := &results[i]
+
+		group.Go(func() error {
+			result.Lookup, result.Err = s.LookupBanner(ctx, result.FeatureID, tagIDs, useLastRevision)
+
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+
+	return results
+}
@@ -0,0 +1,34 @@
+package banner
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sony/gobreaker"
+
+	"avito-backend-trainee-2024/pkg/errs"
+)
+
+// NewBreaker returns circuit breaker for banner lookups. It opens after consecutiveFailures connectivity errors
+// in a row and lets one probe request through after openTimeout, errors of queries themselves don't count
+func NewBreaker(consecutiveFailures uint32, openTimeout time.Duration) *gobreaker.CircuitBreaker {
+	return gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:    "banner lookup",
+		Timeout: openTimeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= consecutiveFailures
+		},
+		IsSuccessful: func(err error) bool {
+			return !errors.Is(err, errs.ErrUnavailable)
+		},
+	})
+}
+
+// mapBreakerError marks errors of rejected by breaker requests as unavailability of the database
+func mapBreakerError(err error) error {
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		return errs.Wrap(errs.ErrUnavailable, err)
+	}
+
+	return err
+}
@@ -0,0 +1,206 @@
+package banner
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+)
+
+// CachePolicy defines how long cached banners are served: during TTL entry is fresh, during following StaleTTL
+// it's still served, but triggers background refresh. If database is unavailable, entry is served
+// during StaleIfError after TTL. Missing banners are cached for NegativeTTL, zero means they aren't cached.
+// Database queries filling the cache are bounded by FetchTimeout, zero means defaultFetchTimeout
+type CachePolicy struct {
+	TTL          time.Duration
+	StaleTTL     time.Duration
+	StaleIfError time.Duration
+	NegativeTTL  time.Duration
+	FetchTimeout time.Duration
+}
+
+const defaultFetchTimeout = 5 * time.Second
+
+// retention returns how long entry must be kept in cache to be served by any of the rules
+func (p CachePolicy) retention() time.Duration {
+	return p.TTL + max(p.StaleTTL, p.StaleIfError)
+}
+
+func (p CachePolicy) fetchTimeout() time.Duration {
+	if p.FetchTimeout <= 0 {
+		return defaultFetchTimeout
+	}
+
+	return p.FetchTimeout
+}
+
+// cacheEntry is a banner stored in cache along with the time it needs revalidation after.
+// Missing entry means there is no banner with the key
+type cacheEntry struct {
+	Banner     *entity.Banner `json:"banner,omitempty"`
+	Missing    bool           `json:"missing,omitempty"`
+	FreshUntil time.Time      `json:"fresh_until"`
+}
+
+// cacheCounters count lookups by the way they were served, see entity.CacheStats
+type cacheCounters struct {
+	indexHits        atomic.Int64
+	hits             atomic.Int64
+	staleHits        atomic.Int64
+	negativeHits     atomic.Int64
+	misses           atomic.Int64
+	bypasses         atomic.Int64
+	staleIfErrorHits atomic.Int64
+}
+
+// CacheStats returns counters of user banner lookups since the start of the instance
+func (s *Service) CacheStats() entity.CacheStats {
+	return entity.CacheStats{
+		IndexHits:        s.counters.indexHits.Load(),
+		Hits:             s.counters.hits.Load(),
+		StaleHits:        s.counters.staleHits.Load(),
+		NegativeHits:     s.counters.negativeHits.Load(),
+		Misses:           s.counters.misses.Load(),
+		Bypasses:         s.counters.bypasses.Load(),
+		StaleIfErrorHits: s.counters.staleIfErrorHits.Load(),
+	}
+}
+
+// getCached returns cached banner, undecodable entry, e.g. written by other version of the service, is treated as a miss
+func (s *Service) getCached(ctx context.Context, key string) (*cacheEntry, bool) {
+	cached, found := s.Cache.Get(ctx, key)
+	if !found {
+		return nil, false
+	}
+
+	var entry cacheEntry
+
+	if err := json.Unmarshal(cached, &entry); err != nil || (entry.Banner == nil && !entry.Missing) {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+func (s *Service) setCached(ctx context.Context, key string, banner *entity.Banner) {
+	encoded, err := json.Marshal(cacheEntry{
+		Banner:     banner,
+		FreshUntil: time.Now().Add(s.CachePolicy.TTL),
+	})
+	if err != nil {
+		s.logger.Errorf("error occurred encoding banner %v for cache: %v", banner.ID, err)
+
+		return
+	}
+
+	s.Cache.Set(ctx, key, encoded, s.CachePolicy.retention())
+}
+
+// setMissing caches absence of banner with provided key, it's never served stale
+func (s *Service) setMissing(ctx context.Context, key string) {
+	if s.CachePolicy.NegativeTTL <= 0 {
+		return
+	}
+
+	encoded, err := json.Marshal(cacheEntry{
+		Missing:    true,
+		FreshUntil: time.Now().Add(s.CachePolicy.NegativeTTL),
+	})
+	if err != nil {
+		s.logger.Errorf("error occurred encoding missing banner %v for cache: %v", key, err)
+
+		return
+	}
+
+	s.Cache.Set(ctx, key, encoded, s.CachePolicy.NegativeTTL)
+}
+
+// queryBanner reads banner matching tags from database through circuit breaker if there is one
+func (s *Service) queryBanner(ctx context.Context, featureID int, tagIDs []int) (*entity.Banner, error) {
+	if s.Breaker == nil {
+		return s.BannerRepo.MatchBanner(ctx, featureID, tagIDs, s.Matching)
+	}
+
+	res, err := s.Breaker.Execute(func() (any, error) {
+		return s.BannerRepo.MatchBanner(ctx, featureID, tagIDs, s.Matching)
+	})
+	if err != nil {
+		return nil, mapBreakerError(err)
+	}
+
+	return res.(*entity.Banner), nil
+}
+
+// fetchBanner reads banner from database and puts it to cache
+func (s *Service) fetchBanner(ctx context.Context, key string, featureID int, tagIDs []int) (*entity.Banner, error) {
+	banner, err := s.queryBanner(ctx, featureID, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if banner == nil {
+		s.setMissing(ctx, key)
+
+		return nil, ErrNoSuchBanner
+	}
+
+	s.setCached(ctx, key, banner)
+
+	return banner, nil
+}
+
+// loadBanner fetches banner missing in cache, concurrent calls for the same key share one database query
+func (s *Service) loadBanner(ctx context.Context, key string, featureID int, tagIDs []int) (*entity.Banner, error) {
+	res, err, _ := s.group.Do(key, func() (any, error) {
+		ctx, cancel := s.fetchContext(ctx)
+		defer cancel()
+
+		return s.fetchBanner(ctx, key, featureID, tagIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*entity.Banner), nil
+}
+
+// fetchContext returns context for shared database query: it must not be canceled with the request that started it,
+// other requests wait for it too, but it's bounded by fetch timeout so a hanging query doesn't hold them forever
+func (s *Service) fetchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), s.CachePolicy.fetchTimeout())
+}
+
+// revalidate refreshes stale cached banner in background, at most one refresh per key runs at a time
+func (s *Service) revalidate(ctx context.Context, key string, featureID int, tagIDs []int) {
+	// result is delivered to buffered channel that nobody reads, so it doesn't block
+	s.group.DoChan(key, func() (any, error) {
+		ctx, cancel := s.fetchContext(ctx)
+		defer cancel()
+
+		banner, err := s.fetchBanner(ctx, key, featureID, tagIDs)
+		if err != nil {
+			s.logger.Errorf("error occurred revalidating cached banner %v: %v", key, err)
+		}
+
+		return banner, err
+	})
+}
+
+// staleIfError returns banner from cached entry if it's within stale-if-error window, nil entry is looked up in cache
+func (s *Service) staleIfError(ctx context.Context, key string, entry *cacheEntry) (*entity.Banner, bool) {
+	if entry == nil {
+		var found bool
+
+		if entry, found = s.getCached(ctx, key); !found {
+			return nil, false
+		}
+	}
+
+	if entry.Missing || time.Since(entry.FreshUntil) > s.CachePolicy.StaleIfError {
+		return nil, false
+	}
+
+	return entry.Banner, true
+}
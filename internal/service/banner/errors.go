@@ -1,9 +1,32 @@
 package banner
 
-import "errors"
+import (
+	"fmt"
+
+	"avito-backend-trainee-2024/pkg/errs"
+)
 
 var (
-	ErrNoSuchFeature = errors.New("no such feature")
-	ErrNoSuchTag     = errors.New("no such tag")
-	ErrNoSuchBanner  = errors.New("no such banner")
+	ErrNoSuchFeature = errs.New(errs.ErrInvalid, "no such feature")
+	ErrNoSuchTag     = errs.New(errs.ErrInvalid, "no such tag")
+	ErrNoSuchBanner  = errs.New(errs.ErrNotFound, "no such banner")
+	ErrBannerExists  = errs.New(errs.ErrConflict, "banner with this feature and tags already exists")
+
+	// ErrFeatureNotFound and ErrTagNotFound are returned when banners are listed by missing feature or tag,
+	// unlike ErrNoSuchFeature and ErrNoSuchTag which mean banner refers to missing one
+	ErrFeatureNotFound = errs.New(errs.ErrNotFound, "no such feature")
+	ErrTagNotFound     = errs.New(errs.ErrNotFound, "no such tag")
+
+	ErrInvalidActiveWindow = errs.New(errs.ErrInvalid, "active_until must be after active_from")
 )
+
+// ConflictError is returned when another banner already has the same feature and tags
+type ConflictError struct {
+	BannerID int
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v: banner_id = %v", ErrBannerExists, e.BannerID)
+}
+
+func (e *ConflictError) Unwrap() error { return ErrBannerExists }
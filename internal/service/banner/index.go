@@ -0,0 +1,265 @@
+package banner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+)
+
+type IndexRepo interface {
+	// GetBannerChanges returns banners changed and deleted since cursor, zero cursor means all banners
+	GetBannerChanges(ctx context.Context, cursor uint64) (*entity.BannerChangeSet, error)
+}
+
+// Index is an in-memory copy of all banners keyed by feature and sorted set of tags along with default banners
+// of features. It is loaded with Load and refreshed incrementally by Run, so reads from it never touch the database.
+// It matches tags exactly only. If refreshes keep failing for longer than max staleness, index stops answering
+// until it's reloaded, so callers fall back to the cache and the database
+type Index struct {
+	repo            IndexRepo
+	refreshInterval time.Duration
+	maxStaleness    time.Duration
+	logger          *logrus.Logger
+
+	mu          sync.RWMutex
+	loaded      bool
+	banners     map[string]*entity.Banner
+	keys        map[int]string         // banner id -> key in banners
+	defaults    map[int]*entity.Banner // feature id -> default banner of the feature
+	invalidated map[string]time.Time   // key -> time it was changed by this instance
+	features    map[int]time.Time      // feature id -> time its default banner was changed by this instance
+	cursor      uint64                 // cursor of changes to read on next refresh
+	refreshedAt time.Time              // time the last successful load or refresh started at
+}
+
+func NewIndex(repo IndexRepo, refreshInterval, maxStaleness time.Duration, logger *logrus.Logger) *Index {
+	return &Index{
+		repo:            repo,
+		refreshInterval: refreshInterval,
+		maxStaleness:    maxStaleness,
+		logger:          logger,
+		banners:         make(map[string]*entity.Banner),
+		keys:            make(map[int]string),
+		defaults:        make(map[int]*entity.Banner),
+		invalidated:     make(map[string]time.Time),
+		features:        make(map[int]time.Time),
+	}
+}
+
+// Get returns banner with provided feature and tags or default banner of the feature if there is no such banner.
+// Second value reports if index can answer: it can't if it isn't loaded, wasn't refreshed for longer than max
+// staleness or the key or the feature is invalidated, then nil banner doesn't mean there is no such banner
+func (i *Index) Get(featureID int, tagIDs []int) (*entity.Banner, bool) {
+	key := bannerKey(featureID, tagIDs)
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if !i.loaded {
+		return nil, false
+	}
+
+	if i.stale() {
+		return nil, false
+	}
+
+	if _, ok := i.invalidated[key]; ok {
+		return nil, false
+	}
+
+	if _, ok := i.features[featureID]; ok {
+		return nil, false
+	}
+
+	if banner, ok := i.banners[key]; ok {
+		return banner, true
+	}
+
+	return i.defaults[featureID], true
+}
+
+// stale reports if index wasn't refreshed for longer than max staleness. Must be called with lock held
+func (i *Index) stale() bool {
+	return i.maxStaleness > 0 && time.Since(i.refreshedAt) > i.maxStaleness
+}
+
+// Invalidate makes index skip banner with provided feature and tags until next refresh picks up its change
+func (i *Index) Invalidate(featureID int, tagIDs []int) {
+	key := bannerKey(featureID, tagIDs)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.invalidated[key] = time.Now()
+}
+
+// InvalidateFeature makes index skip all banners of provided feature until next refresh picks up the change
+// of its default banner
+func (i *Index) InvalidateFeature(featureID int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.features[featureID] = time.Now()
+}
+
+// clearInvalidated removes keys and features invalidated before refresh started, their changes are already
+// in the index. Must be called with write lock held
+func (i *Index) clearInvalidated(refreshStartedAt time.Time) {
+	for key, invalidatedAt := range i.invalidated {
+		if invalidatedAt.Before(refreshStartedAt) {
+			delete(i.invalidated, key)
+		}
+	}
+
+	for featureID, invalidatedAt := range i.features {
+		if invalidatedAt.Before(refreshStartedAt) {
+			delete(i.features, featureID)
+		}
+	}
+}
+
+// Load replaces content of the index with all banners from the database
+func (i *Index) Load(ctx context.Context) error {
+	startedAt := time.Now()
+
+	changes, err := i.repo.GetBannerChanges(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	byKey := make(map[string]*entity.Banner, len(changes.Changed))
+	keys := make(map[int]string, len(changes.Changed))
+	defaults := make(map[int]*entity.Banner)
+
+	for _, banner := range changes.Changed {
+		key := bannerKey(banner.FeatureID, banner.TagIDs)
+
+		byKey[key] = banner
+		keys[banner.ID] = key
+
+		if banner.IsDefault {
+			defaults[banner.FeatureID] = banner
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.banners = byKey
+	i.keys = keys
+	i.defaults = defaults
+	i.cursor = changes.Cursor
+	i.refreshedAt = startedAt
+	i.loaded = true
+	i.clearInvalidated(startedAt)
+
+	return nil
+}
+
+// refresh applies to the index banners changed since previous refresh and removes deleted ones
+func (i *Index) refresh(ctx context.Context) error {
+	startedAt := time.Now()
+
+	i.mu.RLock()
+	cursor := i.cursor
+	i.mu.RUnlock()
+
+	changes, err := i.repo.GetBannerChanges(ctx, cursor)
+	if err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, banner := range changes.Changed {
+		// feature or tags of the banner could change, so entry with previous key is removed
+		if oldKey, ok := i.keys[banner.ID]; ok {
+			if old, ok := i.banners[oldKey]; ok && old.ID == banner.ID {
+				delete(i.banners, oldKey)
+			}
+		}
+
+		key := bannerKey(banner.FeatureID, banner.TagIDs)
+
+		// other banner could have had this key before it was changed, it will be re-added if it still has it
+		if other, ok := i.banners[key]; ok && other.ID != banner.ID {
+			delete(i.keys, other.ID)
+		}
+
+		i.banners[key] = banner
+		i.keys[banner.ID] = key
+
+		i.setDefault(banner)
+	}
+
+	for _, id := range changes.DeletedIDs {
+		if key, ok := i.keys[id]; ok {
+			if banner, ok := i.banners[key]; ok && banner.ID == id {
+				delete(i.banners, key)
+			}
+
+			delete(i.keys, id)
+		}
+
+		for featureID, banner := range i.defaults {
+			if banner.ID == id {
+				delete(i.defaults, featureID)
+			}
+		}
+	}
+
+	i.cursor = changes.Cursor
+	i.refreshedAt = startedAt
+	i.clearInvalidated(startedAt)
+
+	return nil
+}
+
+// setDefault updates default banners of features with changed banner, it could stop being default or move
+// to other feature. Must be called with write lock held
+func (i *Index) setDefault(banner *entity.Banner) {
+	for featureID, current := range i.defaults {
+		if current.ID == banner.ID {
+			delete(i.defaults, featureID)
+		}
+	}
+
+	if banner.IsDefault {
+		i.defaults[banner.FeatureID] = banner
+	}
+}
+
+// Run loads index if it isn't loaded yet and refreshes it every refresh interval until ctx is done. Index that
+// wasn't refreshed for longer than max staleness is reloaded, deletions it missed may be forgotten by the database
+func (i *Index) Run(ctx context.Context) {
+	ticker := time.NewTicker(i.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		i.mu.RLock()
+		loaded := i.loaded && !i.stale()
+		i.mu.RUnlock()
+
+		var err error
+		if loaded {
+			err = i.refresh(ctx)
+		} else {
+			err = i.Load(ctx)
+		}
+
+		if err != nil {
+			i.logger.Errorf("error occurred refreshing banner index: %v", err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package banner
+
+import (
+	"context"
+	"time"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+)
+
+// RunChangesListener evicts cached banners changed by any instance until ctx is done, lost connection is
+// re-established every retryInterval. Notifications sent while connection was down are lost, so after reconnection
+// cache is flushed and index is reloaded
+func (s *Service) RunChangesListener(ctx context.Context, retryInterval time.Duration) {
+	listenedBefore := false
+
+	onListen := func() {
+		if listenedBefore {
+			s.refreshAll(ctx)
+		}
+
+		listenedBefore = true
+	}
+
+	onChange := func(change entity.BannerChange) {
+		if change.Old != nil {
+			s.invalidate(ctx, *change.Old)
+		}
+
+		if change.New != nil {
+			s.invalidate(ctx, *change.New)
+		}
+	}
+
+	for {
+		err := s.BannerRepo.ListenBannerChanges(ctx, onListen, onChange)
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.logger.Errorf("error occurred listening banner changes, retrying in %v: %v", retryInterval, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// refreshAll drops all cached banners and reloads index
+func (s *Service) refreshAll(ctx context.Context) {
+	s.Cache.Flush(ctx)
+
+	if s.Index == nil {
+		return
+	}
+
+	if err := s.Index.Load(ctx); err != nil {
+		s.logger.Errorf("error occurred reloading banner index: %v", err)
+	}
+}
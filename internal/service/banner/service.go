@@ -2,22 +2,39 @@ package banner
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sony/gobreaker"
+	"golang.org/x/sync/singleflight"
 
 	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/pkg/errs"
 
+	bannerrepo "avito-backend-trainee-2024/internal/repository/postgres/banner"
+	schemautils "avito-backend-trainee-2024/pkg/utils/schema"
 	sliceutils "avito-backend-trainee-2024/pkg/utils/slice"
 )
 
 type BannerRepo interface {
-	GetAllBanners(ctx context.Context, offset, limit int) ([]*entity.Banner, error)
-	GetBannersWithFeatureAndTag(ctx context.Context, featureID, tagID int, offset, limit int) ([]*entity.Banner, error)
+	GetAllBanners(ctx context.Context, status entity.BannerStatus, offset, limit int) ([]*entity.Banner, error)
+	GetBannersWithFeatureAndTag(ctx context.Context, featureID, tagID int, status entity.BannerStatus, offset, limit int) ([]*entity.Banner, error)
 	GetBannerByID(ctx context.Context, id int) (*entity.Banner, error)
 	GetBannerByFeatureAndTags(ctx context.Context, featureID int, tagIDs []int) (*entity.Banner, error)
+	MatchBanner(ctx context.Context, featureID int, tagIDs []int, matching entity.BannerMatching) (*entity.Banner, error)
 	CreateBanner(ctx context.Context, banner entity.Banner) (*entity.Banner, error)
-	UpdateBanner(ctx context.Context, id int, updateModel entity.Banner) error
+	UpdateBanner(ctx context.Context, id int, update entity.BannerUpdate) error
 	DeleteBanner(ctx context.Context, id int) (*entity.Banner, error)
+	GetBannerVersions(ctx context.Context, bannerID int, offset, limit int) ([]*entity.BannerVersion, error)
+	GetBannerVersion(ctx context.Context, bannerID, version int) (*entity.BannerVersion, error)
+	RestoreBannerVersion(ctx context.Context, bannerID, version, restoredBy int) error
+	ListenBannerChanges(ctx context.Context, onListen func(), onChange func(change entity.BannerChange)) error
 }
 
 type FeatureRepo interface {
@@ -29,37 +46,136 @@ type TagRepo interface {
 	GetTagByID(ctx context.Context, id int) (*entity.Tag, error)
 }
 
+// Cache is a cache of user banners keyed by feature and sorted tags, changed banners are deleted from it
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+	Delete(ctx context.Context, key string)
+	Flush(ctx context.Context)
+}
+
 type Service struct {
 	BannerRepo  BannerRepo
 	FeatureRepo FeatureRepo
 	TagRepo     TagRepo
+	Cache       Cache
+	CachePolicy CachePolicy
+	// Matching is a way user tags are matched against tags of banners, empty means exact
+	Matching entity.BannerMatching
+	// Breaker stops lookups in database while it's unavailable, nil means no breaker
+	Breaker *gobreaker.CircuitBreaker
+
+	// Index serves user banners without database, nil means banners are always read from database or cache
+	Index *Index
+
+	group    singleflight.Group
+	counters cacheCounters
+	logger   *logrus.Logger
 }
 
-func New(bannerRepo BannerRepo, featureRepo FeatureRepo, tagRepo TagRepo) *Service {
+func New(
+	bannerRepo BannerRepo,
+	featureRepo FeatureRepo,
+	tagRepo TagRepo,
+	cache Cache,
+	cachePolicy CachePolicy,
+	matching entity.BannerMatching,
+	breaker *gobreaker.CircuitBreaker,
+	index *Index,
+	logger *logrus.Logger,
+) *Service {
 	return &Service{
 		BannerRepo:  bannerRepo,
 		FeatureRepo: featureRepo,
 		TagRepo:     tagRepo,
+		Cache:       cache,
+		CachePolicy: cachePolicy,
+		Matching:    matching,
+		Breaker:     breaker,
+		Index:       index,
+		logger:      logger,
+	}
+}
+
+// bannerKey returns key of the banner with provided feature and tags, order and duplicates of tags don't matter
+func bannerKey(featureID int, tagIDs []int) string {
+	sorted := slices.Clone(tagIDs)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	tags := make([]string, 0, len(sorted))
+	for _, tagID := range sorted {
+		tags = append(tags, strconv.Itoa(tagID))
 	}
+
+	return fmt.Sprintf("%v:%v", featureID, strings.Join(tags, ","))
 }
 
-func (s *Service) GetAllBanners(ctx context.Context, offset, limit int) ([]*entity.Banner, error) {
-	return s.BannerRepo.GetAllBanners(ctx, offset, limit)
+// exactMatching reports if banners are matched by exact set of tags, so lookup by other tags can't return changed banner
+func (s *Service) exactMatching() bool {
+	return s.Matching == "" || s.Matching == entity.BannerMatchingExact
 }
 
-func (s *Service) GetBannersWithFeatureAndTag(ctx context.Context, featureID, tagID int, offset, limit int) ([]*entity.Banner, error) {
+// keyOf returns key identifying provided banner in cache and index
+func keyOf(banner *entity.Banner) entity.BannerKey {
+	return entity.BannerKey{
+		FeatureID: banner.FeatureID,
+		TagIDs:    banner.TagIDs,
+		IsDefault: banner.IsDefault,
+	}
+}
+
+// invalidate removes banner with provided key from cache and index, so next read goes to database.
+// Default banner or banner matched not by exact tags may be returned for any tags of its feature,
+// cache can't find all of them, so it's flushed entirely
+func (s *Service) invalidate(ctx context.Context, key entity.BannerKey) {
+	if key.IsDefault || !s.exactMatching() {
+		s.Cache.Flush(ctx)
+
+		if s.Index != nil {
+			s.Index.InvalidateFeature(key.FeatureID)
+		}
+
+		return
+	}
+
+	s.Cache.Delete(ctx, bannerKey(key.FeatureID, key.TagIDs))
+
+	if s.Index != nil {
+		s.Index.Invalidate(key.FeatureID, key.TagIDs)
+	}
+}
+
+func (s *Service) GetAllBanners(ctx context.Context, status entity.BannerStatus, offset, limit int) ([]*entity.Banner, error) {
+	return s.BannerRepo.GetAllBanners(ctx, status, offset, limit)
+}
+
+func (s *Service) GetBannersWithFeatureAndTag(
+	ctx context.Context,
+	featureID, tagID int,
+	status entity.BannerStatus,
+	offset, limit int,
+) ([]*entity.Banner, error) {
 	// check if provided tag and feature exists
 	tag, err := s.TagRepo.GetTagByID(ctx, tagID)
-	if err != nil || tag == nil {
+	if err != nil {
 		return nil, err
 	}
 
-	feature, err := s.FeatureRepo.GetFeatureByID(ctx, featureID)
-	if err != nil || feature == nil {
+	if tag.ID == 0 {
+		return nil, ErrTagNotFound
+	}
+
+	_, err = s.FeatureRepo.GetFeatureByID(ctx, featureID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrFeatureNotFound
+	}
+
+	if err != nil {
 		return nil, err
 	}
 
-	banners, err := s.BannerRepo.GetBannersWithFeatureAndTag(ctx, featureID, tagID, offset, limit)
+	banners, err := s.BannerRepo.GetBannersWithFeatureAndTag(ctx, featureID, tagID, status, offset, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -67,34 +183,127 @@ func (s *Service) GetBannersWithFeatureAndTag(ctx context.Context, featureID, ta
 	return banners, nil
 }
 
-func (s *Service) GetBannerByFeatureAndTags(ctx context.Context, featureID int, tagIDs []int) (*entity.Banner, error) {
-	slices.Sort(tagIDs) // sort slice
+// BannerLookup is a banner found by feature and tags
+type BannerLookup struct {
+	Banner *entity.Banner
+	// Stale reports that database is unavailable and Banner is the last known value
+	Stale bool
+}
 
-	banner, err := s.BannerRepo.GetBannerByFeatureAndTags(ctx, featureID, tagIDs)
+// GetBannerByFeatureAndTags returns banner with provided feature and tags, see LookupBanner
+func (s *Service) GetBannerByFeatureAndTags(ctx context.Context, featureID int, tagIDs []int, useLastRevision bool) (*entity.Banner, error) {
+	lookup, err := s.LookupBanner(ctx, featureID, tagIDs, useLastRevision)
 	if err != nil {
 		return nil, err
 	}
 
-	if banner == nil {
-		return nil, ErrNoSuchBanner
+	return lookup.Banner, nil
+}
+
+// LookupBanner returns banner of provided feature matching tags, see Matching, or default banner of the feature.
+// Unless useLastRevision is set, banner is taken from the index or cache which may be behind the database
+// by index refresh interval or cache TTL. Index is used only with exact matching.
+// If database is unavailable, last known banner is returned marked as stale
+func (s *Service) LookupBanner(ctx context.Context, featureID int, tagIDs []int, useLastRevision bool) (*BannerLookup, error) {
+	key := bannerKey(featureID, tagIDs)
+
+	var (
+		banner *entity.Banner
+		entry  *cacheEntry
+		err    error
+	)
+
+	if useLastRevision {
+		s.counters.bypasses.Add(1)
+
+		banner, err = s.fetchBanner(ctx, key, featureID, tagIDs)
+	} else {
+		if s.Index != nil && s.exactMatching() {
+			if indexed, ok := s.Index.Get(featureID, tagIDs); ok {
+				s.counters.indexHits.Add(1)
+
+				if indexed == nil {
+					return nil, ErrNoSuchBanner
+				}
+
+				return &BannerLookup{Banner: indexed}, nil
+			}
+		}
+
+		var found bool
+
+		if entry, found = s.getCached(ctx, key); found {
+			staleFor := time.Since(entry.FreshUntil)
+
+			switch {
+			case entry.Missing && staleFor <= 0:
+				s.counters.negativeHits.Add(1)
+
+				return nil, ErrNoSuchBanner
+			case entry.Missing:
+				// expired absence is never served
+			case staleFor <= 0:
+				s.counters.hits.Add(1)
+
+				return &BannerLookup{Banner: entry.Banner}, nil
+			case staleFor <= s.CachePolicy.StaleTTL:
+				// stale banner is served immediately while fresh one is fetched in background
+				s.counters.staleHits.Add(1)
+				s.revalidate(ctx, key, featureID, tagIDs)
+
+				return &BannerLookup{Banner: entry.Banner}, nil
+			}
+		}
+
+		s.counters.misses.Add(1)
+
+		banner, err = s.loadBanner(ctx, key, featureID, tagIDs)
 	}
 
-	return banner, nil
+	if errors.Is(err, errs.ErrUnavailable) {
+		if stale, ok := s.staleIfError(ctx, key, entry); ok {
+			s.counters.staleIfErrorHits.Add(1)
+
+			return &BannerLookup{Banner: stale, Stale: true}, nil
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &BannerLookup{Banner: banner}, nil
 }
 
 // validateBanner checks if associated with banner tags and feature are presented in db
+// and banner content matches feature's content schema
 func (s *Service) validateBanner(ctx context.Context, banner entity.Banner, validateFeature, validateTags bool) error {
 	if validateFeature {
 		feature, err := s.FeatureRepo.GetFeatureByID(ctx, banner.FeatureID)
-		if err != nil || feature == nil {
+		if errors.Is(err, sql.ErrNoRows) {
 			return ErrNoSuchFeature
 		}
+
+		if err != nil {
+			return err
+		}
+
+		// content and all its variants must match JSON schema of the feature
+		if err = schemautils.Validate(feature.ContentSchema, banner.Content.Data); err != nil {
+			return err
+		}
+
+		for _, variant := range banner.Variants {
+			if err = schemautils.Validate(feature.ContentSchema, variant.Content); err != nil {
+				return fmt.Errorf("variant %v: %w", variant.Key, err)
+			}
+		}
 	}
 
 	if validateTags {
 		tags, err := s.TagRepo.GetTagsWithIDs(ctx, banner.TagIDs)
 		if err != nil {
-			return errors.Join(ErrNoSuchTag, err)
+			return err
 		}
 
 		// compare two slices: sorted(tagIDs) and tags by tag.ID field
@@ -112,24 +321,251 @@ func (s *Service) validateBanner(ctx context.Context, banner entity.Banner, vali
 	return nil
 }
 
+// checkUniqueness returns ConflictError if banner other than provided one has the same feature and tags
+func (s *Service) checkUniqueness(ctx context.Context, id, featureID int, tagIDs []int) error {
+	tagIDs = slices.Clone(tagIDs)
+	slices.Sort(tagIDs)
+
+	existing, err := s.BannerRepo.GetBannerByFeatureAndTags(ctx, featureID, slices.Compact(tagIDs))
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.ID != id {
+		return &ConflictError{BannerID: existing.ID}
+	}
+
+	return nil
+}
+
+// mapConflict turns uniqueness violation reported by db, e.g. when concurrent request won the race, into ConflictError
+func (s *Service) mapConflict(ctx context.Context, id, featureID int, tagIDs []int, err error) error {
+	if !errors.Is(err, bannerrepo.ErrBannerExists) {
+		return err
+	}
+
+	if checkErr := s.checkUniqueness(ctx, id, featureID, tagIDs); checkErr != nil {
+		return checkErr
+	}
+
+	return ErrBannerExists
+}
+
 func (s *Service) CreateBanner(ctx context.Context, banner entity.Banner) (*entity.Banner, error) {
 	// firstly validate that feature and tags associated with banner exists in db
 	if err := s.validateBanner(ctx, banner, true, true); err != nil {
 		return nil, err
 	}
 
-	return s.BannerRepo.CreateBanner(ctx, banner)
+	if err := s.checkUniqueness(ctx, 0, banner.FeatureID, banner.TagIDs); err != nil {
+		return nil, err
+	}
+
+	if banner.Rollout != nil && banner.Rollout.Percent < entity.FullRollout && banner.Rollout.Previous == nil {
+		previous, err := s.liveSnapshot(ctx, banner.FeatureID, banner.TagIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		banner.Rollout.Previous = previous
+	}
+
+	created, err := s.BannerRepo.CreateBanner(ctx, banner)
+	if err != nil {
+		return nil, s.mapConflict(ctx, 0, banner.FeatureID, banner.TagIDs, err)
+	}
+
+	s.invalidate(ctx, keyOf(created))
+
+	return created, nil
 }
 
-func (s *Service) UpdateBanner(ctx context.Context, id int, updateModel entity.Banner) error {
+func (s *Service) UpdateBanner(ctx context.Context, id int, update entity.BannerUpdate) error {
+	// content must be validated against the schema if either feature, content or its variants change
+	validateFeature := update.FeatureID != 0 || len(update.Content) != 0 || len(update.Variants) != 0
+	// feature and tags must stay unique if either of them changes
+	checkUniqueness := update.FeatureID != 0 || len(update.TagIDs) != 0
+
+	// current banner is needed to invalidate its cached copy even if feature and tags don't change
+	current, err := s.getBanner(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// not updating fields are taken from current banner
+	banner := update.Apply(*current)
+
+	// kept bound of activation window may conflict with the new one
+	if banner.ActiveFrom != nil && banner.ActiveUntil != nil && !banner.ActiveUntil.After(*banner.ActiveFrom) {
+		return ErrInvalidActiveWindow
+	}
+
 	// firstly validate that feature and tags associated with banner exists in db
-	if err := s.validateBanner(ctx, updateModel, updateModel.FeatureID != 0, len(updateModel.TagIDs) != 0); err != nil {
+	if err := s.validateBanner(ctx, banner, validateFeature, len(update.TagIDs) != 0); err != nil {
 		return err
 	}
 
-	return s.BannerRepo.UpdateBanner(ctx, id, updateModel)
+	if checkUniqueness {
+		if err := s.checkUniqueness(ctx, id, banner.FeatureID, banner.TagIDs); err != nil {
+			return err
+		}
+	}
+
+	update.Rollout = rolloutOf(current, update)
+
+	if err = s.BannerRepo.UpdateBanner(ctx, id, update); err != nil {
+		return s.mapConflict(ctx, id, banner.FeatureID, banner.TagIDs, err)
+	}
+
+	s.invalidate(ctx, keyOf(current))
+	s.invalidate(ctx, keyOf(&banner))
+
+	return nil
+}
+
+// rolloutOf returns rollout of the banner after update, nil means it isn't changed. Users outside of rollout see
+// the last content shown to all users: it's saved when content of active banner isn't being rolled out and either
+// the content is replaced or rollout starts, so content and percent may be changed by the same or separate requests.
+// It's kept until rollout is finished
+func rolloutOf(current *entity.Banner, update entity.BannerUpdate) *entity.BannerRollout {
+	if update.Rollout != nil && update.Rollout.Percent >= entity.FullRollout {
+		return &entity.BannerRollout{Percent: entity.FullRollout}
+	}
+
+	replacesContent := len(update.Content) != 0 || update.Variants != nil
+	rollingOut := current.Rollout != nil && current.Rollout.Percent < entity.FullRollout
+
+	rollout := entity.BannerRollout{Percent: entity.FullRollout}
+	if current.Rollout != nil {
+		rollout = *current.Rollout
+	}
+
+	switch {
+	case current.IsActive && !rollingOut && replacesContent:
+		rollout.Previous = snapshotOf(current)
+	case update.Rollout == nil:
+		return nil
+	case current.IsActive && !rollingOut && rollout.Previous == nil:
+		rollout.Previous = snapshotOf(current)
+	}
+
+	if update.Rollout != nil {
+		rollout.Percent = update.Rollout.Percent
+	}
+
+	return &rollout
+}
+
+// liveSnapshot returns content of the banner users with provided feature and tags see now, e.g. default banner of
+// the feature, so banner created with partial rollout keeps showing it to users outside of rollout. Nil means
+// they see nothing
+func (s *Service) liveSnapshot(ctx context.Context, featureID int, tagIDs []int) (*entity.BannerSnapshot, error) {
+	banner, err := s.BannerRepo.MatchBanner(ctx, featureID, tagIDs, s.Matching)
+	if err != nil || banner == nil || !banner.IsActiveAt(time.Now()) {
+		return nil, err
+	}
+
+	return snapshotOf(banner), nil
+}
+
+// snapshotOf returns content of the banner along with its variants
+func snapshotOf(banner *entity.Banner) *entity.BannerSnapshot {
+	return &entity.BannerSnapshot{
+		Content:  banner.Content.Data,
+		Variants: banner.Variants,
+	}
 }
 
 func (s *Service) DeleteBanner(ctx context.Context, id int) (*entity.Banner, error) {
-	return s.BannerRepo.DeleteBanner(ctx, id)
+	deleted, err := s.BannerRepo.DeleteBanner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(ctx, keyOf(deleted))
+
+	return deleted, nil
+}
+
+func (s *Service) GetBannerVersions(ctx context.Context, id int, offset, limit int) ([]*entity.BannerVersion, error) {
+	if err := s.ensureBannerExists(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.BannerRepo.GetBannerVersions(ctx, id, offset, limit)
+}
+
+func (s *Service) RestoreBannerVersion(ctx context.Context, id, version, restoredBy int) error {
+	current, err := s.getBanner(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	restored, err := s.BannerRepo.GetBannerVersion(ctx, id, version)
+	if err != nil {
+		return err
+	}
+
+	if err = s.validateRestored(ctx, restored); err != nil {
+		return err
+	}
+
+	if err = s.BannerRepo.RestoreBannerVersion(ctx, id, version, restoredBy); err != nil {
+		// restored feature and tags may be taken by another banner
+		return s.mapConflict(ctx, id, restored.FeatureID, restored.TagIDs, err)
+	}
+
+	s.invalidateRestored(ctx, current)
+
+	return nil
+}
+
+// validateRestored checks version is still valid: its feature and tags could be deleted and content schema
+// of the feature changed since it was saved
+func (s *Service) validateRestored(ctx context.Context, version *entity.BannerVersion) error {
+	err := s.validateBanner(ctx, entity.Banner{
+		TagIDs:    slices.Clone(version.TagIDs),
+		FeatureID: version.FeatureID,
+		Content:   version.Content,
+		Variants:  version.Variants,
+	}, true, true)
+
+	switch {
+	case errors.Is(err, ErrNoSuchFeature):
+		return bannerrepo.ErrVersionFeatureDeleted
+	case errors.Is(err, ErrNoSuchTag):
+		return bannerrepo.ErrVersionTagDeleted
+	default:
+		return err
+	}
+}
+
+// invalidateRestored invalidates banner before restoration and the restored one, which may have other feature and tags
+func (s *Service) invalidateRestored(ctx context.Context, before *entity.Banner) {
+	s.invalidate(ctx, keyOf(before))
+
+	restored, err := s.BannerRepo.GetBannerByID(ctx, before.ID)
+	if err == nil && restored != nil {
+		s.invalidate(ctx, keyOf(restored))
+	}
+}
+
+// getBanner returns banner with provided id or ErrNoSuchBanner if there is no such banner
+func (s *Service) getBanner(ctx context.Context, id int) (*entity.Banner, error) {
+	banner, err := s.BannerRepo.GetBannerByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if banner == nil {
+		return nil, ErrNoSuchBanner
+	}
+
+	return banner, nil
+}
+
+func (s *Service) ensureBannerExists(ctx context.Context, id int) error {
+	_, err := s.getBanner(ctx, id)
+
+	return err
 }
@@ -0,0 +1,7 @@
+package feature
+
+import "avito-backend-trainee-2024/pkg/errs"
+
+var (
+	ErrNoSuchFeature = errs.New(errs.ErrNotFound, "no such feature")
+)
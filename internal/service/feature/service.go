@@ -0,0 +1,102 @@
+package feature
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+
+	schemautils "avito-backend-trainee-2024/pkg/utils/schema"
+)
+
+type FeatureRepo interface {
+	GetFeatureByID(ctx context.Context, id int) (*entity.Feature, error)
+	GetFeatures(ctx context.Context, offset, limit int) ([]*entity.Feature, error)
+	CreateFeature(ctx context.Context, feature entity.Feature) (*entity.Feature, error)
+	UpdateFeatureName(ctx context.Context, id int, name string) (*entity.Feature, error)
+	UpdateFeatureContentSchema(ctx context.Context, id int, schema json.RawMessage) (*entity.Feature, error)
+	DeleteFeature(ctx context.Context, id int, cascade bool) (*entity.Feature, error)
+}
+
+// Cache is a cache of user banners that must be invalidated after cascade deletion of feature's banners
+type Cache interface {
+	Flush(ctx context.Context)
+}
+
+type Service struct {
+	FeatureRepo FeatureRepo
+	Cache       Cache
+}
+
+func New(featureRepo FeatureRepo, cache Cache) *Service {
+	return &Service{
+		FeatureRepo: featureRepo,
+		Cache:       cache,
+	}
+}
+
+func (s *Service) GetFeatureByID(ctx context.Context, id int) (*entity.Feature, error) {
+	feature, err := s.FeatureRepo.GetFeatureByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoSuchFeature
+	}
+
+	return feature, err
+}
+
+func (s *Service) GetFeatures(ctx context.Context, offset, limit int) ([]*entity.Feature, error) {
+	return s.FeatureRepo.GetFeatures(ctx, offset, limit)
+}
+
+func (s *Service) CreateFeature(ctx context.Context, feature entity.Feature) (*entity.Feature, error) {
+	if len(feature.ContentSchema) != 0 {
+		if _, err := schemautils.Compile(feature.ContentSchema); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.FeatureRepo.CreateFeature(ctx, feature)
+}
+
+func (s *Service) RenameFeature(ctx context.Context, id int, name string) (*entity.Feature, error) {
+	feature, err := s.FeatureRepo.UpdateFeatureName(ctx, id, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoSuchFeature
+	}
+
+	return feature, err
+}
+
+// SetContentSchema attaches JSON schema to the feature, content of feature's banners will be validated against it
+func (s *Service) SetContentSchema(ctx context.Context, id int, schema json.RawMessage) (*entity.Feature, error) {
+	if _, err := schemautils.Compile(schema); err != nil {
+		return nil, err
+	}
+
+	feature, err := s.FeatureRepo.UpdateFeatureContentSchema(ctx, id, schema)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoSuchFeature
+	}
+
+	return feature, err
+}
+
+// DeleteFeature deletes the feature, with cascade its banners are deleted too, otherwise feature must have no banners
+func (s *Service) DeleteFeature(ctx context.Context, id int, cascade bool) (*entity.Feature, error) {
+	feature, err := s.FeatureRepo.DeleteFeature(ctx, id, cascade)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoSuchFeature
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cascade {
+		s.Cache.Flush(ctx)
+	}
+
+	return feature, nil
+}
@@ -0,0 +1,175 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+)
+
+type JobRepo interface {
+	CreateJob(ctx context.Context, job entity.Job) (*entity.Job, error)
+	GetJobByID(ctx context.Context, id int) (*entity.Job, error)
+	ClaimPendingJob(ctx context.Context, leaseTimeout time.Duration) (*entity.Job, error)
+	ExtendJobLease(ctx context.Context, id int) error
+	FinishJob(ctx context.Context, id int, status entity.JobStatus, deleted int, errMsg string) error
+}
+
+type BannerRepo interface {
+	DeleteBanners(ctx context.Context, featureID, tagID int) (int, error)
+}
+
+// Cache is a cache of user banners that must be invalidated after banners deletion
+type Cache interface {
+	Flush(ctx context.Context)
+}
+
+type Service struct {
+	JobRepo    JobRepo
+	BannerRepo BannerRepo
+	Cache      Cache
+
+	pollInterval time.Duration
+	leaseTimeout time.Duration
+	wakeup       chan struct{}
+	logger       *logrus.Logger
+}
+
+// New creates job service, running job which lease isn't extended for leaseTimeout is run again by any instance
+func New(
+	jobRepo JobRepo,
+	bannerRepo BannerRepo,
+	cache Cache,
+	pollInterval, leaseTimeout time.Duration,
+	logger *logrus.Logger,
+) *Service {
+	return &Service{
+		JobRepo:      jobRepo,
+		BannerRepo:   bannerRepo,
+		Cache:        cache,
+		pollInterval: pollInterval,
+		leaseTimeout: leaseTimeout,
+		wakeup:       make(chan struct{}, 1),
+		logger:       logger,
+	}
+}
+
+// CreateDeleteBannersJob saves deletion job and notifies worker about it, deletion itself is done by Run
+func (s *Service) CreateDeleteBannersJob(ctx context.Context, featureID, tagID int) (*entity.Job, error) {
+	job, err := s.JobRepo.CreateJob(ctx, entity.Job{
+		FeatureID: featureID,
+		TagID:     tagID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// don't block if worker is already notified
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+func (s *Service) GetJobByID(ctx context.Context, id int) (*entity.Job, error) {
+	return s.JobRepo.GetJobByID(ctx, id)
+}
+
+// Run executes pending jobs until ctx is done. Jobs are picked up on notification from CreateDeleteBannersJob
+// and every poll interval, so jobs created by other instances, left pending after restart or left running by crashed
+// instances are executed too
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.runPendingJobs(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wakeup:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) runPendingJobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := s.JobRepo.ClaimPendingJob(ctx, s.leaseTimeout)
+		if err != nil {
+			s.logger.Errorf("error occurred claiming pending job: %v", err)
+			return
+		}
+
+		if job == nil {
+			return
+		}
+
+		s.runJob(ctx, job)
+	}
+}
+
+func (s *Service) runJob(ctx context.Context, job *entity.Job) {
+	status, errMsg := entity.JobStatusDone, ""
+
+	stopLease := s.keepLease(ctx, job.ID)
+
+	deleted, err := s.BannerRepo.DeleteBanners(ctx, job.FeatureID, job.TagID)
+
+	stopLease()
+
+	if err != nil {
+		s.logger.Errorf("error occurred running job %v: %v", job.ID, err)
+
+		status, errMsg = entity.JobStatusFailed, err.Error()
+	}
+
+	if deleted > 0 {
+		s.Cache.Flush(ctx)
+	}
+
+	// job must be finished even if ctx is canceled
+	if err = s.JobRepo.FinishJob(context.WithoutCancel(ctx), job.ID, status, deleted, errMsg); err != nil {
+		s.logger.Errorf("error occurred finishing job %v: %v", job.ID, err)
+	}
+}
+
+// keepLease extends lease of the running job every third of lease timeout until returned function is called,
+// so other instances don't run the job again while it's running
+func (s *Service) keepLease(ctx context.Context, id int) func() {
+	if s.leaseTimeout <= 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(s.leaseTimeout / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := s.JobRepo.ExtendJobLease(ctx, id); err != nil && ctx.Err() == nil {
+				s.logger.Errorf("error occurred extending lease of job %v: %v", id, err)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,7 @@
+package tag
+
+import "avito-backend-trainee-2024/pkg/errs"
+
+var (
+	ErrNoSuchTag = errs.New(errs.ErrNotFound, "no such tag")
+)
@@ -0,0 +1,92 @@
+package tag
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+)
+
+type TagRepo interface {
+	GetTagByID(ctx context.Context, id int) (*entity.Tag, error)
+	GetTags(ctx context.Context, offset, limit int) ([]*entity.Tag, error)
+	CreateTags(ctx context.Context, names []string) ([]*entity.Tag, error)
+	UpdateTagName(ctx context.Context, id int, name string) (*entity.Tag, error)
+	DeleteTag(ctx context.Context, id int, cascade bool) (*entity.Tag, error)
+}
+
+// Cache is a cache of user banners that must be invalidated after cascade deletion of tag's banners
+type Cache interface {
+	Flush(ctx context.Context)
+}
+
+type Service struct {
+	TagRepo TagRepo
+	Cache   Cache
+}
+
+func New(tagRepo TagRepo, cache Cache) *Service {
+	return &Service{
+		TagRepo: tagRepo,
+		Cache:   cache,
+	}
+}
+
+func (s *Service) GetTagByID(ctx context.Context, id int) (*entity.Tag, error) {
+	tag, err := s.TagRepo.GetTagByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag.ID == 0 {
+		return nil, ErrNoSuchTag
+	}
+
+	return tag, nil
+}
+
+func (s *Service) GetTags(ctx context.Context, offset, limit int) ([]*entity.Tag, error) {
+	return s.TagRepo.GetTags(ctx, offset, limit)
+}
+
+func (s *Service) CreateTag(ctx context.Context, name string) (*entity.Tag, error) {
+	tags, err := s.TagRepo.CreateTags(ctx, []string{name})
+	if err != nil {
+		return nil, err
+	}
+
+	return tags[0], nil
+}
+
+// CreateTags creates tags with provided names in a single transaction
+func (s *Service) CreateTags(ctx context.Context, names []string) ([]*entity.Tag, error) {
+	return s.TagRepo.CreateTags(ctx, names)
+}
+
+func (s *Service) RenameTag(ctx context.Context, id int, name string) (*entity.Tag, error) {
+	tag, err := s.TagRepo.UpdateTagName(ctx, id, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoSuchTag
+	}
+
+	return tag, err
+}
+
+// DeleteTag deletes the tag, with cascade banners having it are deleted too, otherwise tag must belong to no banner
+func (s *Service) DeleteTag(ctx context.Context, id int, cascade bool) (*entity.Tag, error) {
+	tag, err := s.TagRepo.DeleteTag(ctx, id, cascade)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNoSuchTag
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cascade {
+		s.Cache.Flush(ctx)
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,22 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultExpiration makes entry expire after default expiration of the cache
+	DefaultExpiration time.Duration = 0
+	// NoExpiration makes entry live until it's deleted or evicted
+	NoExpiration time.Duration = -1
+)
+
+// Cache stores values by keys for provided ttl. All backends have the same semantics:
+// expired entries are never returned, Delete and Flush take effect for every following Get
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+	Delete(ctx context.Context, key string)
+	Flush(ctx context.Context)
+}
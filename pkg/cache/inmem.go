@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+)
+
+// InMem is an unbounded in-process cache, expired entries are removed every cleanup interval
+type InMem struct {
+	cache *gocache.Cache
+}
+
+func NewInMem(expiration, cleanupInterval time.Duration) *InMem {
+	return &InMem{
+		cache: gocache.New(expiration, cleanupInterval),
+	}
+}
+
+func (c *InMem) Get(_ context.Context, key string) ([]byte, bool) {
+	val, found := c.cache.Get(key)
+	if !found {
+		return nil, false
+	}
+
+	return val.([]byte), true
+}
+
+func (c *InMem) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	c.cache.Set(key, value, ttl)
+}
+
+func (c *InMem) Delete(_ context.Context, key string) {
+	c.cache.Delete(key)
+}
+
+func (c *InMem) Flush(_ context.Context) {
+	c.cache.Flush()
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+type lruEntry struct {
+	value     []byte
+	expiresAt time.Time // zero means no expiration
+}
+
+// LRU is an in-process cache holding at most size entries, least recently used entries are evicted first
+type LRU struct {
+	cache      *lru.Cache[string, lruEntry]
+	expiration time.Duration
+}
+
+func NewLRU(size int, expiration time.Duration) (*LRU, error) {
+	cache, err := lru.New[string, lruEntry](size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LRU{
+		cache:      cache,
+		expiration: expiration,
+	}, nil
+}
+
+func (c *LRU) Get(_ context.Context, key string) ([]byte, bool) {
+	entry, found := c.cache.Get(key)
+	if !found {
+		return nil, false
+	}
+
+	// expired entries are removed lazily
+	if !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt) {
+		c.cache.Remove(key)
+
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+func (c *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	if ttl == DefaultExpiration {
+		ttl = c.expiration
+	}
+
+	entry := lruEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	c.cache.Add(key, entry)
+}
+
+func (c *LRU) Delete(_ context.Context, key string) {
+	c.cache.Remove(key)
+}
+
+func (c *LRU) Flush(_ context.Context) {
+	c.cache.Purge()
+}
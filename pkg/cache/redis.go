@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// flushBatchSize is a number of keys scanned and deleted at once by Flush
+const flushBatchSize = 100
+
+// Redis is a cache shared by all instances, stored in Redis or any server speaking its protocol.
+// All keys are prefixed, so Flush removes only entries of this cache. Errors of the server are logged
+// and treated as cache misses, so unavailable cache doesn't fail requests
+type Redis struct {
+	client     *redis.Client
+	prefix     string
+	expiration time.Duration
+	logger     *logrus.Logger
+}
+
+func NewRedis(client *redis.Client, prefix string, expiration time.Duration, logger *logrus.Logger) *Redis {
+	return &Redis{
+		client:     client,
+		prefix:     prefix,
+		expiration: expiration,
+		logger:     logger,
+	}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool) {
+	val, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false
+	}
+
+	if err != nil {
+		c.logger.Errorf("error occurred getting %v from redis cache: %v", key, err)
+
+		return nil, false
+	}
+
+	return val, true
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	switch {
+	case ttl == DefaultExpiration:
+		ttl = c.expiration
+	case ttl < 0:
+		ttl = 0 // zero expiration means no expiration for redis
+	}
+
+	if err := c.client.Set(ctx, c.prefix+key, value, ttl).Err(); err != nil {
+		c.logger.Errorf("error occurred setting %v to redis cache: %v", key, err)
+	}
+}
+
+func (c *Redis) Delete(ctx context.Context, key string) {
+	if err := c.client.Del(ctx, c.prefix+key).Err(); err != nil {
+		c.logger.Errorf("error occurred deleting %v from redis cache: %v", key, err)
+	}
+}
+
+func (c *Redis) Flush(ctx context.Context) {
+	iter := c.client.Scan(ctx, 0, c.prefix+"*", flushBatchSize).Iterator()
+
+	keys := make([]string, 0, flushBatchSize)
+
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+
+		if len(keys) == flushBatchSize {
+			c.delete(ctx, keys)
+			keys = keys[:0]
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		c.logger.Errorf("error occurred scanning redis cache keys: %v", err)
+	}
+
+	c.delete(ctx, keys)
+}
+
+func (c *Redis) delete(ctx context.Context, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		c.logger.Errorf("error occurred flushing redis cache: %v", err)
+	}
+}
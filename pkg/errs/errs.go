@@ -0,0 +1,48 @@
+package errs
+
+import "errors"
+
+// Kinds of errors shared by all layers, every domain error wraps one of them,
+// errors not wrapping any kind are considered internal ones
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrInvalid      = errors.New("invalid")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnavailable  = errors.New("unavailable")
+)
+
+type kindError struct {
+	kind error
+	msg  string
+}
+
+func (e *kindError) Error() string { return e.msg }
+
+func (e *kindError) Unwrap() error { return e.kind }
+
+// New returns error with provided message, errors.Is reports it is of provided kind
+func New(kind error, msg string) error {
+	return &kindError{
+		kind: kind,
+		msg:  msg,
+	}
+}
+
+type wrapError struct {
+	kind error
+	err  error
+}
+
+func (e *wrapError) Error() string { return e.err.Error() }
+
+func (e *wrapError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// Wrap returns err marked with provided kind, errors.Is reports both the kind and the wrapped error
+func Wrap(kind error, err error) error {
+	return &wrapError{
+		kind: kind,
+		err:  err,
+	}
+}
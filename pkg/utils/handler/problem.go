@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	schemautils "avito-backend-trainee-2024/pkg/utils/schema"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	problemType        = "about:blank"
+)
+
+// Problem is an error response body in RFC 7807 problem details format
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes why particular field of the request is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// CodeFromStatus returns stable machine-readable code of the error with provided status
+func CodeFromStatus(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return "invalid_request"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusServiceUnavailable:
+		return "unavailable"
+	default:
+		if statusCode >= http.StatusInternalServerError {
+			return "internal_error"
+		}
+
+		return strings.ReplaceAll(strings.ToLower(http.StatusText(statusCode)), " ", "_")
+	}
+}
+
+// WriteErrResponseAndLog logs logMsg and responds with problem details, respMsg is its detail.
+// Detail of server errors is never sent to the client, so it can't leak internals
+func WriteErrResponseAndLog(
+	rw http.ResponseWriter,
+	req *http.Request,
+	logger *logrus.Logger,
+	statusCode int,
+	logMsg string,
+	respMsg string,
+	fieldErrs ...FieldError,
+) {
+	requestID := middleware.GetReqID(req.Context())
+
+	if logMsg != "" {
+		logger.WithField("request_id", requestID).Error(logMsg)
+	}
+
+	if statusCode >= http.StatusInternalServerError {
+		respMsg = ""
+	}
+
+	problem := Problem{
+		Type:      problemType,
+		Title:     http.StatusText(statusCode),
+		Status:    statusCode,
+		Code:      CodeFromStatus(statusCode),
+		Detail:    respMsg,
+		Instance:  req.URL.Path,
+		RequestID: requestID,
+		Errors:    fieldErrs,
+	}
+
+	rw.Header().Set("Content-Type", problemContentType)
+	rw.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(rw).Encode(problem); err != nil {
+		logger.Errorf("error occurred writing response: %s", err)
+	}
+}
+
+// FieldErrors returns details of request validation error, either validator or JSON schema one
+func FieldErrors(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fieldErrs := make([]FieldError, 0, len(validationErrs))
+
+		for _, fe := range validationErrs {
+			constraint := fe.Tag()
+			if fe.Param() != "" {
+				constraint = fmt.Sprintf("%v=%v", constraint, fe.Param())
+			}
+
+			fieldErrs = append(fieldErrs, FieldError{
+				Field:   fe.Field(),
+				Message: fmt.Sprintf("must satisfy '%v' constraint", constraint),
+			})
+		}
+
+		return fieldErrs
+	}
+
+	var schemaErr *schemautils.ValidationError
+	if errors.As(err, &schemaErr) {
+		fieldErrs := make([]FieldError, 0, len(schemaErr.Violations))
+
+		for _, violation := range schemaErr.Violations {
+			fieldErrs = append(fieldErrs, FieldError{
+				Field:   violation.Path,
+				Message: violation.Message,
+			})
+		}
+
+		return fieldErrs
+	}
+
+	return nil
+}
+
+// JSONTagName makes validator report fields by their json names, use with validator.RegisterTagNameFunc
+func JSONTagName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
@@ -2,24 +2,30 @@ package handler
 
 import (
 	"errors"
-	"github.com/sirupsen/logrus"
 	"net/http"
 	"strconv"
 	"strings"
-)
-
-func WriteErrResponseAndLog(rw http.ResponseWriter, logger *logrus.Logger, statusCode int, logMsg string, respMsg string) {
-	if logMsg != "" {
-		logger.Errorf(logMsg)
-	}
 
-	rw.WriteHeader(statusCode)
+	"avito-backend-trainee-2024/pkg/errs"
+)
 
-	if respMsg != "" {
-		_, err := rw.Write([]byte(respMsg))
-		if err != nil {
-			logger.Errorf("error occurred writing response: %s", err)
-		}
+// StatusFromErr returns http status code corresponding to the kind of the error, unknown errors are internal ones
+func StatusFromErr(err error) int {
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errs.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, errs.ErrInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, errs.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, errs.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, errs.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
 	}
 }
 
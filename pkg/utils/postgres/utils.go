@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// connectionExceptionClass is a class of SQLSTATE codes of connection errors
+const connectionExceptionClass = "08"
+
+// unavailableCodes are SQLSTATE codes server responds with when it's shutting down or starting up
+var unavailableCodes = map[string]struct{}{
+	"57P01": {}, // admin_shutdown
+	"57P02": {}, // crash_shutdown
+	"57P03": {}, // cannot_connect_now
+}
+
+// IsConnectivityError reports if err means database can't be reached, as opposed to errors of the query itself
+func IsConnectivityError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if strings.HasPrefix(pgErr.Code, connectionExceptionClass) {
+			return true
+		}
+
+		_, ok := unavailableCodes[pgErr.Code]
+
+		return ok
+	}
+
+	return false
+}
+
+// IntArray is integer[] column value. Empty array and NULL are scanned as empty slice
+type IntArray []int
+
+// Scan implements sql.Scanner
+func (a *IntArray) Scan(src any) error {
+	ints := make([]int, 0)
+
+	if err := pgtype.NewMap().SQLScanner(&ints).Scan(src); err != nil {
+		return err
+	}
+
+	if ints == nil {
+		ints = make([]int, 0)
+	}
+
+	*a = ints
+
+	return nil
+}
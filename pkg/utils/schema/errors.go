@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"fmt"
+
+	"avito-backend-trainee-2024/pkg/errs"
+)
+
+var (
+	ErrInvalidSchema = errs.New(errs.ErrInvalid, "invalid JSON schema")
+)
+
+// Violation describes why value at Path of the document does not match the schema
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when document does not match the schema
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("document does not match the schema: %v violation(s)", len(e.Violations))
+}
+
+func (e *ValidationError) Unwrap() error { return errs.ErrInvalid }
@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const resourceName = "mem://schema.json"
+
+// Compile parses and compiles JSON schema, returns ErrInvalidSchema if schema is malformed
+func Compile(schema json.RawMessage) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+
+	if err := compiler.AddResource(resourceName, bytes.NewReader(schema)); err != nil {
+		return nil, errors.Join(ErrInvalidSchema, err)
+	}
+
+	compiled, err := compiler.Compile(resourceName)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidSchema, err)
+	}
+
+	return compiled, nil
+}
+
+// Validate checks that document matches the schema, returns *ValidationError listing all violations if it doesn't
+func Validate(schema, document json.RawMessage) error {
+	compiled, err := Compile(schema)
+	if err != nil {
+		return err
+	}
+
+	var doc any
+
+	if err = json.Unmarshal(document, &doc); err != nil {
+		return err
+	}
+
+	err = compiled.Validate(doc)
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	return &ValidationError{Violations: violations(validationErr, nil)}
+}
+
+// violations collects leaf causes of the validation error, they describe actual mismatches
+func violations(err *jsonschema.ValidationError, res []Violation) []Violation {
+	if len(err.Causes) == 0 {
+		return append(res, Violation{
+			Path:    err.InstanceLocation,
+			Message: err.Message,
+		})
+	}
+
+	for _, cause := range err.Causes {
+		res = violations(cause, res)
+	}
+
+	return res
+}
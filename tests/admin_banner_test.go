@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/pkg/cache"
+	"avito-backend-trainee-2024/pkg/errs"
+
+	bannerrepo "avito-backend-trainee-2024/internal/repository/postgres/banner"
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+	handlerutils "avito-backend-trainee-2024/pkg/utils/handler"
+)
+
+func (s *Suite) TestCreateBannerWithTakenFeatureAndTags() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	existing, err := s.bannerRepo.GetBannerByFeatureAndTags(ctx, 1, []int{1, 2})
+	assertions.NoError(err)
+	assertions.NotNil(existing)
+
+	_, err = s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{2, 1},
+		FeatureID: 1,
+		Content: entity.Content{
+			Data: json.RawMessage(`{"title": "duplicate"}`),
+		},
+	})
+
+	var conflictErr *bannerservice.ConflictError
+	assertions.True(errors.As(err, &conflictErr))
+	assertions.Equal(existing.ID, conflictErr.BannerID)
+
+	// unique index doesn't let duplicate in bypassing the service
+	_, err = s.bannerRepo.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{2, 1},
+		FeatureID: 1,
+		Content: entity.Content{
+			Data: json.RawMessage(`{"title": "duplicate"}`),
+		},
+	})
+	assertions.ErrorIs(err, bannerrepo.ErrBannerExists)
+}
+
+func (s *Suite) TestUpdateAndDeleteNotExistingBanner() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	err := s.bannerRepo.UpdateBanner(ctx, math.MaxInt32, entity.BannerUpdate{
+		IsActive: ptr(true),
+	})
+	assertions.ErrorIs(err, errs.ErrNotFound)
+
+	_, err = s.bannerRepo.DeleteBanner(ctx, math.MaxInt32)
+	assertions.ErrorIs(err, errs.ErrNotFound)
+}
+
+func (s *Suite) TestGetBannersWithFeatureAndTagPagination() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"paginated_tag", "other_tag"})
+	assertions.NoError(err)
+
+	paginatedTag, otherTag := tags[0].ID, tags[1].ID
+
+	var created []*entity.Banner
+
+	// banners of the same feature without paginated tag go first, they must not consume the limit
+	for _, tagIDs := range [][]int{{otherTag}, {paginatedTag}, {otherTag, paginatedTag}} {
+		banner, err := s.bannerRepo.CreateBanner(ctx, entity.Banner{
+			TagIDs:    tagIDs,
+			FeatureID: 1,
+			Content: entity.Content{
+				Data: json.RawMessage(`{"title": "some_title"}`),
+			},
+		})
+		assertions.NoError(err)
+
+		created = append(created, banner)
+	}
+
+	firstPage, err := s.bannerRepo.GetBannersWithFeatureAndTag(ctx, 1, paginatedTag, "", 0, 1)
+	assertions.NoError(err)
+	assertions.Len(firstPage, 1)
+	assertions.Equal(created[1].ID, firstPage[0].ID)
+
+	secondPage, err := s.bannerRepo.GetBannersWithFeatureAndTag(ctx, 1, paginatedTag, "", 1, 1)
+	assertions.NoError(err)
+	assertions.Len(secondPage, 1)
+	assertions.Equal(created[2].ID, secondPage[0].ID)
+
+	// tag set is matched exactly regardless of order and duplicates
+	banner, err := s.bannerRepo.GetBannerByFeatureAndTags(ctx, 1, []int{paginatedTag, otherTag, paginatedTag})
+	assertions.NoError(err)
+	assertions.NotNil(banner)
+	assertions.Equal(created[2].ID, banner.ID)
+
+	for _, banner := range created {
+		_, err = s.bannerRepo.DeleteBanner(ctx, banner.ID)
+		assertions.NoError(err)
+	}
+}
+
+// missingFeatureRepo reports every requested feature as missing
+type missingFeatureRepo struct{}
+
+func (missingFeatureRepo) GetFeatureByID(context.Context, int) (*entity.Feature, error) {
+	return nil, sql.ErrNoRows
+}
+
+func TestGetBannersWithMissingFeatureNotFound(t *testing.T) {
+	service := bannerservice.New(
+		&countingBannerRepo{}, missingFeatureRepo{}, existingTagRepo{},
+		cache.NewInMem(time.Minute, time.Minute), bannerservice.CachePolicy{TTL: time.Minute},
+		entity.BannerMatchingExact, nil, nil, logrus.New(),
+	)
+
+	_, err := service.GetBannersWithFeatureAndTag(context.Background(), 1, 1, "", 0, 10)
+	require.ErrorIs(t, err, bannerservice.ErrFeatureNotFound)
+	require.Equal(t, http.StatusNotFound, handlerutils.StatusFromErr(err))
+}
+
+func (s *Suite) TestGetBannersWithMissingTagNotFound() {
+	assertions := s.Require()
+
+	_, err := s.bannerService.GetBannersWithFeatureAndTag(context.Background(), 1, math.MaxInt32, "", 0, 10)
+	assertions.ErrorIs(err, bannerservice.ErrTagNotFound)
+	assertions.ErrorIs(err, errs.ErrNotFound)
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+
+	userbannerhandler "avito-backend-trainee-2024/internal/handler/banner/user"
+	midlewares "avito-backend-trainee-2024/internal/handler/middleware"
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+	jwtutils "avito-backend-trainee-2024/pkg/utils/jwt"
+)
+
+// revisionRecordingService serves the same active banner for all features but missing ones
+// and records if last revision was requested
+type revisionRecordingService struct {
+	useLastRevision bool
+	missing         map[int]bool
+	variants        []entity.BannerVariant
+}
+
+func (s *revisionRecordingService) LookupBanner(_ context.Context, featureID int, tagIDs []int, useLastRevision bool) (*bannerservice.BannerLookup, error) {
+	s.useLastRevision = useLastRevision
+
+	if s.missing[featureID] {
+		return nil, bannerservice.ErrNoSuchBanner
+	}
+
+	return &bannerservice.BannerLookup{Banner: &entity.Banner{
+		FeatureID: featureID,
+		TagIDs:    tagIDs,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": "title"}`)},
+		Variants:  s.variants,
+		Activity:  entity.Activity{IsActive: true},
+	}}, nil
+}
+
+func (s *revisionRecordingService) LookupBanners(ctx context.Context, featureIDs []int, tagIDs []int, useLastRevision bool) []bannerservice.BatchLookup {
+	results := make([]bannerservice.BatchLookup, 0, len(featureIDs))
+
+	for _, featureID := range featureIDs {
+		lookup, err := s.LookupBanner(ctx, featureID, tagIDs, useLastRevision)
+		results = append(results, bannerservice.BatchLookup{FeatureID: featureID, Lookup: lookup, Err: err})
+	}
+
+	return results
+}
+
+func TestUserBannerAlwaysFreshClaim(t *testing.T) {
+	const secret = "secret"
+
+	cases := []struct {
+		name            string
+		payload         map[string]any
+		query           string
+		useLastRevision bool
+	}{
+		{
+			name:            "always fresh user without param",
+			payload:         map[string]any{"id": 1, "username": "user", "is_admin": false, "always_fresh": true},
+			query:           "feature_id=1&tag_ids=1",
+			useLastRevision: true,
+		},
+		{
+			name:            "regular user without param",
+			payload:         map[string]any{"id": 1, "username": "user", "is_admin": false, "always_fresh": false},
+			query:           "feature_id=1&tag_ids=1",
+			useLastRevision: false,
+		},
+		{
+			name:            "regular user with param",
+			payload:         map[string]any{"id": 1, "username": "user", "is_admin": false, "always_fresh": false},
+			query:           "feature_id=1&tag_ids=1&use_last_revision=true",
+			useLastRevision: true,
+		},
+		{
+			name:            "token issued before always fresh claim",
+			payload:         map[string]any{"id": 1, "username": "user", "is_admin": false},
+			query:           "feature_id=1&tag_ids=1",
+			useLastRevision: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &revisionRecordingService{}
+			logger := logrus.New()
+			handler := userbannerhandler.New(
+				service, userbannerhandler.CacheControl{}, logger, validator.New(),
+				midlewares.JWTAuthentication("token", secret, logger),
+			)
+
+			token, err := jwtutils.CreateJWT(tc.payload, jwt.SigningMethodHS256, secret)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+			req.Header.Set("token", token)
+			// header set by client must not grant fresh reads
+			req.Header.Set("always_fresh", "true")
+
+			recorder := httptest.NewRecorder()
+			handler.Routes().ServeHTTP(recorder, req)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, tc.useLastRevision, service.useLastRevision)
+		})
+	}
+}
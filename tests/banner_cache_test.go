@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/pkg/cache"
+
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+)
+
+func (s *Suite) TestBannerCacheInvalidation() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"cached_tag_1", "cached_tag_2"})
+	assertions.NoError(err)
+
+	tagIDs := []int{tags[0].ID, tags[1].ID}
+
+	created, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    tagIDs,
+		FeatureID: 1,
+		Content: entity.Content{
+			Data: json.RawMessage(`{"title": "cached"}`),
+		},
+	})
+	assertions.NoError(err)
+
+	banner, err := s.bannerService.GetBannerByFeatureAndTags(ctx, 1, []int{tags[1].ID, tags[0].ID}, false)
+	assertions.NoError(err)
+	assertions.JSONEq(`{"title": "cached"}`, string(banner.Content.Data))
+
+	err = s.bannerService.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		Content: json.RawMessage(`{"title": "updated"}`),
+	})
+	assertions.NoError(err)
+
+	// tags order doesn't matter, both requests use the same cache entry which is evicted by update
+	banner, err = s.bannerService.GetBannerByFeatureAndTags(ctx, 1, tagIDs, false)
+	assertions.NoError(err)
+	assertions.JSONEq(`{"title": "updated"}`, string(banner.Content.Data))
+
+	_, err = s.bannerService.DeleteBanner(ctx, created.ID)
+	assertions.NoError(err)
+
+	_, err = s.bannerService.GetBannerByFeatureAndTags(ctx, 1, tagIDs, false)
+	assertions.ErrorIs(err, bannerservice.ErrNoSuchBanner)
+}
+
+func (s *Suite) TestBannerNegativeCacheInvalidatedOnCreate() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"negative_cached_tag"})
+	assertions.NoError(err)
+
+	tagIDs := []int{tags[0].ID}
+
+	_, err = s.bannerService.GetBannerByFeatureAndTags(ctx, 1, tagIDs, false)
+	assertions.ErrorIs(err, bannerservice.ErrNoSuchBanner)
+
+	created, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    tagIDs,
+		FeatureID: 1,
+		Content: entity.Content{
+			Data: json.RawMessage(`{"title": "created"}`),
+		},
+	})
+	assertions.NoError(err)
+
+	// cached absence of the banner is evicted by creation
+	banner, err := s.bannerService.GetBannerByFeatureAndTags(ctx, 1, tagIDs, false)
+	assertions.NoError(err)
+	assertions.JSONEq(`{"title": "created"}`, string(banner.Content.Data))
+
+	_, err = s.bannerService.DeleteBanner(ctx, created.ID)
+	assertions.NoError(err)
+}
+
+func (s *Suite) TestBannerCacheInvalidationAcrossInstances() {
+	assertions := s.Require()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// other instance with its own cache learns about changes from notifications only
+	other := bannerservice.New(
+		s.bannerRepo, s.featureRepo, s.tagRepo,
+		cache.NewInMem(time.Hour, time.Hour), bannerservice.CachePolicy{TTL: time.Hour},
+		entity.BannerMatchingExact, nil, nil, logrus.New(),
+	)
+
+	go other.RunChangesListener(ctx, 10*time.Millisecond)
+
+	// give listener time to subscribe, notifications sent before it are lost
+	time.Sleep(100 * time.Millisecond)
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"notified_tag"})
+	assertions.NoError(err)
+
+	tagIDs := []int{tags[0].ID}
+
+	created, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    tagIDs,
+		FeatureID: 1,
+		Content: entity.Content{
+			Data: json.RawMessage(`{"title": "before"}`),
+		},
+	})
+	assertions.NoError(err)
+
+	banner, err := other.GetBannerByFeatureAndTags(ctx, 1, tagIDs, false)
+	assertions.NoError(err)
+	assertions.JSONEq(`{"title": "before"}`, string(banner.Content.Data))
+
+	err = s.bannerService.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		Content: json.RawMessage(`{"title": "after"}`),
+	})
+	assertions.NoError(err)
+
+	assertions.Eventually(func() bool {
+		banner, err := other.GetBannerByFeatureAndTags(ctx, 1, tagIDs, false)
+		return err == nil && strings.Contains(string(banner.Content.Data), "after")
+	}, 5*time.Second, 20*time.Millisecond)
+
+	_, err = s.bannerService.DeleteBanner(ctx, created.ID)
+	assertions.NoError(err)
+
+	assertions.Eventually(func() bool {
+		_, err := other.GetBannerByFeatureAndTags(ctx, 1, tagIDs, false)
+		return errors.Is(err, bannerservice.ErrNoSuchBanner)
+	}, 5*time.Second, 20*time.Millisecond)
+}
@@ -0,0 +1,231 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+
+	bannerrepo "avito-backend-trainee-2024/internal/repository/postgres/banner"
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+)
+
+func (s *Suite) TestBannerIndexRefresh() {
+	assertions := s.Require()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	index := bannerservice.NewIndex(bannerrepo.New(s.db), 10*time.Millisecond, time.Minute, logrus.New())
+
+	banner, loaded := index.Get(1, []int{1, 2})
+	assertions.False(loaded)
+	assertions.Nil(banner)
+
+	assertions.NoError(index.Load(ctx))
+
+	// tags order doesn't matter
+	banner, loaded = index.Get(1, []int{2, 1})
+	assertions.True(loaded)
+	assertions.NotNil(banner)
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"indexed_tag", "reindexed_tag"})
+	assertions.NoError(err)
+
+	created, err := s.bannerRepo.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{tags[0].ID},
+		FeatureID: 1,
+		Content: entity.Content{
+			Data: json.RawMessage(`{"title": "indexed"}`),
+		},
+	})
+	assertions.NoError(err)
+
+	go index.Run(ctx)
+
+	assertions.Eventually(func() bool {
+		banner, _ := index.Get(1, []int{tags[0].ID})
+		return banner != nil && banner.ID == created.ID
+	}, time.Second, 10*time.Millisecond)
+
+	// moving banner to other tags removes it from the previous key
+	err = s.bannerRepo.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		TagIDs:  []int{tags[1].ID},
+		Content: json.RawMessage(`{"title": "reindexed"}`),
+	})
+	assertions.NoError(err)
+
+	assertions.Eventually(func() bool {
+		old, _ := index.Get(1, []int{tags[0].ID})
+		banner, _ := index.Get(1, []int{tags[1].ID})
+		return old == nil && banner != nil && string(banner.Content.Data) == `{"title": "reindexed"}`
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = s.bannerRepo.DeleteBanner(ctx, created.ID)
+	assertions.NoError(err)
+
+	assertions.Eventually(func() bool {
+		banner, _ := index.Get(1, []int{tags[1].ID})
+		return banner == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+// staticIndexRepo serves the same banners on every load
+type staticIndexRepo struct {
+	banners []*entity.Banner
+}
+
+func (r *staticIndexRepo) GetBannerChanges(context.Context, uint64) (*entity.BannerChangeSet, error) {
+	return &entity.BannerChangeSet{Changed: r.banners}, nil
+}
+
+func TestBannerIndexFallsBackToDefaultBanner(t *testing.T) {
+	repo := &staticIndexRepo{banners: []*entity.Banner{
+		{ID: 1, FeatureID: 1, TagIDs: []int{1}},
+		{ID: 2, FeatureID: 1, TagIDs: []int{2}, IsDefault: true},
+	}}
+	index := bannerservice.NewIndex(repo, time.Minute, time.Minute, logrus.New())
+
+	require.NoError(t, index.Load(context.Background()))
+
+	banner, loaded := index.Get(1, []int{1})
+	require.True(t, loaded)
+	require.Equal(t, 1, banner.ID)
+
+	banner, loaded = index.Get(1, []int{3})
+	require.True(t, loaded)
+	require.Equal(t, 2, banner.ID)
+
+	// feature without default banner has nothing for unknown tags
+	banner, loaded = index.Get(2, []int{3})
+	require.True(t, loaded)
+	require.Nil(t, banner)
+
+	// change of default banner affects all tags of the feature until the index is reloaded
+	index.InvalidateFeature(1)
+
+	_, loaded = index.Get(1, []int{1})
+	require.False(t, loaded)
+
+	time.Sleep(time.Millisecond)
+	require.NoError(t, index.Load(context.Background()))
+
+	_, loaded = index.Get(1, []int{1})
+	require.True(t, loaded)
+}
+
+// failingIndexRepo serves banners of staticIndexRepo until it is told to fail
+type failingIndexRepo struct {
+	staticIndexRepo
+	failing atomic.Bool
+}
+
+func (r *failingIndexRepo) GetBannerChanges(ctx context.Context, cursor uint64) (*entity.BannerChangeSet, error) {
+	if r.failing.Load() {
+		return nil, errors.New("database is down")
+	}
+
+	return r.staticIndexRepo.GetBannerChanges(ctx, cursor)
+}
+
+func TestBannerIndexStopsAnsweringWhenStale(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo := &failingIndexRepo{staticIndexRepo: staticIndexRepo{banners: []*entity.Banner{
+		{ID: 1, FeatureID: 1, TagIDs: []int{1}},
+	}}}
+	index := bannerservice.NewIndex(repo, 10*time.Millisecond, 100*time.Millisecond, logrus.New())
+
+	require.NoError(t, index.Load(ctx))
+
+	go index.Run(ctx)
+
+	// successful refreshes keep the index fresh
+	time.Sleep(200 * time.Millisecond)
+
+	banner, loaded := index.Get(1, []int{1})
+	require.True(t, loaded)
+	require.Equal(t, 1, banner.ID)
+
+	repo.failing.Store(true)
+
+	require.Eventually(t, func() bool {
+		_, loaded := index.Get(1, []int{1})
+		return !loaded
+	}, time.Second, 10*time.Millisecond)
+
+	repo.failing.Store(false)
+
+	require.Eventually(t, func() bool {
+		_, loaded := index.Get(1, []int{1})
+		return loaded
+	}, time.Second, 10*time.Millisecond)
+}
+
+// changeLogIndexRepo serves all banners on load and queued change sets on refreshes
+type changeLogIndexRepo struct {
+	banners []*entity.Banner
+	changes chan *entity.BannerChangeSet
+	cursors chan uint64 // cursors refreshes were requested with
+}
+
+func (r *changeLogIndexRepo) GetBannerChanges(ctx context.Context, cursor uint64) (*entity.BannerChangeSet, error) {
+	if cursor == 0 {
+		return &entity.BannerChangeSet{Changed: r.banners, Cursor: 1}, nil
+	}
+
+	r.cursors <- cursor
+
+	select {
+	case changes := <-r.changes:
+		return changes, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestBannerIndexAppliesDeletions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo := &changeLogIndexRepo{
+		banners: []*entity.Banner{
+			{ID: 1, FeatureID: 1, TagIDs: []int{1}},
+			{ID: 2, FeatureID: 1, TagIDs: []int{2}, IsDefault: true},
+		},
+		changes: make(chan *entity.BannerChangeSet),
+		cursors: make(chan uint64, 10),
+	}
+	index := bannerservice.NewIndex(repo, time.Millisecond, time.Minute, logrus.New())
+
+	require.NoError(t, index.Load(ctx))
+
+	go index.Run(ctx)
+
+	// refresh continues from the cursor of previous read
+	require.Equal(t, uint64(1), <-repo.cursors)
+	repo.changes <- &entity.BannerChangeSet{
+		Changed:    []*entity.Banner{{ID: 3, FeatureID: 1, TagIDs: []int{3}}},
+		DeletedIDs: []int{1, 2},
+		Cursor:     5,
+	}
+
+	require.Equal(t, uint64(5), <-repo.cursors)
+
+	banner, loaded := index.Get(1, []int{3})
+	require.True(t, loaded)
+	require.Equal(t, 3, banner.ID)
+
+	// deleted default banner isn't a fallback anymore
+	banner, loaded = index.Get(1, []int{1})
+	require.True(t, loaded)
+	require.Nil(t, banner)
+}
@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/pkg/cache"
+	"avito-backend-trainee-2024/pkg/errs"
+
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+)
+
+// countingBannerRepo serves banner lookups from memory and counts them, other methods are not implemented
+type countingBannerRepo struct {
+	bannerservice.BannerRepo
+
+	calls   atomic.Int32
+	release chan struct{} // if not nil, lookups wait until it's closed
+
+	mu      sync.Mutex
+	content string
+	missing bool
+	err     error
+}
+
+func (r *countingBannerRepo) setContent(content string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.content = content
+}
+
+func (r *countingBannerRepo) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.err = err
+}
+
+func (r *countingBannerRepo) MatchBanner(ctx context.Context, featureID int, tagIDs []int, _ entity.BannerMatching) (*entity.Banner, error) {
+	r.calls.Add(1)
+
+	if r.release != nil {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if r.missing {
+		return nil, nil
+	}
+
+	return &entity.Banner{
+		ID:        1,
+		FeatureID: featureID,
+		TagIDs:    tagIDs,
+		Content: entity.Content{
+			Data: json.RawMessage(r.content),
+		},
+	}, nil
+}
+
+func newLookupService(repo *countingBannerRepo, policy bannerservice.CachePolicy, breaker *gobreaker.CircuitBreaker) *bannerservice.Service {
+	return bannerservice.New(
+		repo, nil, nil, cache.NewInMem(time.Minute, time.Minute), policy, entity.BannerMatchingExact, breaker, nil, logrus.New(),
+	)
+}
+
+func TestBannerLookupCoalescesConcurrentMisses(t *testing.T) {
+	repo := &countingBannerRepo{release: make(chan struct{}), content: `{"title": "title"}`}
+	service := newLookupService(repo, bannerservice.CachePolicy{TTL: time.Minute}, nil)
+
+	const requests = 50
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, requests)
+
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := service.GetBannerByFeatureAndTags(context.Background(), 1, []int{2, 1}, false)
+			errs <- err
+		}()
+	}
+
+	// let all requests reach the cache miss before the query finishes
+	require.Eventually(t, func() bool { return repo.calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	close(repo.release)
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, int32(1), repo.calls.Load())
+}
+
+func TestBannerLookupServesStaleWhileRevalidating(t *testing.T) {
+	repo := &countingBannerRepo{content: `{"title": "old"}`}
+	service := newLookupService(repo, bannerservice.CachePolicy{TTL: 10 * time.Millisecond, StaleTTL: time.Minute}, nil)
+	ctx := context.Background()
+
+	banner, err := service.GetBannerByFeatureAndTags(ctx, 1, []int{1}, false)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"title": "old"}`, string(banner.Content.Data))
+
+	repo.setContent(`{"title": "new"}`)
+	time.Sleep(20 * time.Millisecond)
+
+	// expired entry is served as is, refresh runs in background
+	banner, err = service.GetBannerByFeatureAndTags(ctx, 1, []int{1}, false)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"title": "old"}`, string(banner.Content.Data))
+
+	require.Eventually(t, func() bool {
+		banner, err := service.GetBannerByFeatureAndTags(ctx, 1, []int{1}, false)
+		return err == nil && strings.Contains(string(banner.Content.Data), "new")
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestBannerLookupFetchTimesOut(t *testing.T) {
+	// lookups never released hang until fetch timeout even though request context has no deadline
+	repo := &countingBannerRepo{release: make(chan struct{}), content: `{"title": "title"}`}
+	service := newLookupService(repo, bannerservice.CachePolicy{TTL: time.Minute, FetchTimeout: 20 * time.Millisecond}, nil)
+
+	startedAt := time.Now()
+
+	_, err := service.LookupBanner(context.Background(), 1, []int{1}, false)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(startedAt), time.Second)
+}
+
+func TestBannerLookupServesStaleIfDatabaseUnavailable(t *testing.T) {
+	repo := &countingBannerRepo{content: `{"title": "last known"}`}
+	policy := bannerservice.CachePolicy{TTL: 10 * time.Millisecond, StaleIfError: time.Minute}
+	service := newLookupService(repo, policy, bannerservice.NewBreaker(2, time.Minute))
+	ctx := context.Background()
+
+	lookup, err := service.LookupBanner(ctx, 1, []int{1}, false)
+	require.NoError(t, err)
+	require.False(t, lookup.Stale)
+
+	repo.setErr(errs.Wrap(errs.ErrUnavailable, io.ErrUnexpectedEOF))
+	time.Sleep(20 * time.Millisecond)
+
+	// expired banner is served marked as stale, even if last revision is requested
+	for _, useLastRevision := range []bool{false, true, false, true} {
+		lookup, err = service.LookupBanner(ctx, 1, []int{1}, useLastRevision)
+		require.NoError(t, err)
+		require.True(t, lookup.Stale)
+		require.JSONEq(t, `{"title": "last known"}`, string(lookup.Banner.Content.Data))
+	}
+
+	// breaker opened after two failures, so database isn't queried anymore
+	require.Equal(t, int32(3), repo.calls.Load())
+
+	// nothing to serve for banner that was never cached
+	_, err = service.LookupBanner(ctx, 1, []int{2}, false)
+	require.ErrorIs(t, err, errs.ErrUnavailable)
+	require.Equal(t, int32(3), repo.calls.Load())
+}
+
+func TestBannerLookupCachesMissingBanners(t *testing.T) {
+	repo := &countingBannerRepo{missing: true}
+	service := newLookupService(repo, bannerservice.CachePolicy{TTL: time.Minute, NegativeTTL: 20 * time.Millisecond}, nil)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := service.GetBannerByFeatureAndTags(ctx, 1, []int{1}, false)
+		require.ErrorIs(t, err, bannerservice.ErrNoSuchBanner)
+	}
+
+	require.Equal(t, int32(1), repo.calls.Load())
+
+	// absence of banner expires sooner than banners do
+	time.Sleep(30 * time.Millisecond)
+
+	_, err := service.GetBannerByFeatureAndTags(ctx, 1, []int{1}, false)
+	require.ErrorIs(t, err, bannerservice.ErrNoSuchBanner)
+	require.Equal(t, int32(2), repo.calls.Load())
+
+	_, err = service.GetBannerByFeatureAndTags(ctx, 1, []int{1}, true)
+	require.ErrorIs(t, err, bannerservice.ErrNoSuchBanner)
+
+	require.Equal(t, entity.CacheStats{NegativeHits: 2, Misses: 2, Bypasses: 1}, service.CacheStats())
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+
+	bannerrepo "avito-backend-trainee-2024/internal/repository/postgres/banner"
+)
+
+func (s *Suite) TestBannerMatchingModes() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	feature, err := s.featureRepo.CreateFeature(ctx, entity.Feature{Name: "matched_feature"})
+	assertions.NoError(err)
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"matched_tag_1", "matched_tag_2", "matched_tag_3"})
+	assertions.NoError(err)
+
+	create := func(title string, tagIDs []int, priority int, isDefault bool) *entity.Banner {
+		banner, err := s.bannerRepo.CreateBanner(ctx, entity.Banner{
+			TagIDs:    tagIDs,
+			FeatureID: feature.ID,
+			Content: entity.Content{
+				Data: json.RawMessage(`{"title": "` + title + `"}`),
+			},
+			Activity:  entity.Activity{IsActive: true},
+			Priority:  priority,
+			IsDefault: isDefault,
+		})
+		assertions.NoError(err)
+
+		return banner
+	}
+
+	single := create("single", []int{tags[0].ID}, 10, false)
+	pair := create("pair", []int{tags[0].ID, tags[1].ID}, 0, false)
+
+	userTags := []int{tags[0].ID, tags[1].ID, tags[2].ID}
+
+	banner, err := s.bannerRepo.MatchBanner(ctx, feature.ID, userTags, entity.BannerMatchingExact)
+	assertions.NoError(err)
+	assertions.Nil(banner)
+
+	// the subset with most tags wins
+	banner, err = s.bannerRepo.MatchBanner(ctx, feature.ID, userTags, entity.BannerMatchingBestSubset)
+	assertions.NoError(err)
+	assertions.Equal(pair.ID, banner.ID)
+
+	// any common tag matches, the banner with highest priority wins
+	banner, err = s.bannerRepo.MatchBanner(ctx, feature.ID, userTags, entity.BannerMatchingAnyTag)
+	assertions.NoError(err)
+	assertions.Equal(single.ID, banner.ID)
+
+	// banners outside of their activation window are not matched however well they fit
+	later, earlier := time.Now().Add(time.Hour), time.Now().Add(-time.Hour)
+
+	scheduled, err := s.bannerRepo.CreateBanner(ctx, entity.Banner{
+		TagIDs:    userTags,
+		FeatureID: feature.ID,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": "scheduled"}`)},
+		Activity:  entity.Activity{IsActive: true, ActiveFrom: &later},
+	})
+	assertions.NoError(err)
+
+	expired, err := s.bannerRepo.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{tags[1].ID, tags[2].ID},
+		FeatureID: feature.ID,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": "expired"}`)},
+		Activity:  entity.Activity{IsActive: true, ActiveUntil: &earlier},
+		Priority:  100,
+	})
+	assertions.NoError(err)
+
+	banner, err = s.bannerRepo.MatchBanner(ctx, feature.ID, userTags, entity.BannerMatchingBestSubset)
+	assertions.NoError(err)
+	assertions.Equal(pair.ID, banner.ID)
+
+	banner, err = s.bannerRepo.MatchBanner(ctx, feature.ID, userTags, entity.BannerMatchingAnyTag)
+	assertions.NoError(err)
+	assertions.Equal(single.ID, banner.ID)
+
+	fallback := create("default", []int{tags[2].ID}, 0, true)
+
+	_, err = s.bannerRepo.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{tags[1].ID},
+		FeatureID: feature.ID,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": "second default"}`)},
+		IsDefault: true,
+	})
+	assertions.ErrorIs(err, bannerrepo.ErrDefaultBannerExists)
+
+	for _, matching := range []entity.BannerMatching{entity.BannerMatchingExact, entity.BannerMatchingBestSubset} {
+		banner, err = s.bannerRepo.MatchBanner(ctx, feature.ID, []int{tags[1].ID}, matching)
+		assertions.NoError(err)
+		assertions.Equal(fallback.ID, banner.ID)
+	}
+
+	// matching banner is preferred over default one
+	banner, err = s.bannerRepo.MatchBanner(ctx, feature.ID, []int{tags[0].ID}, entity.BannerMatchingExact)
+	assertions.NoError(err)
+	assertions.Equal(single.ID, banner.ID)
+
+	for _, id := range []int{single.ID, pair.ID, scheduled.ID, expired.ID, fallback.ID} {
+		_, err = s.bannerRepo.DeleteBanner(ctx, id)
+		assertions.NoError(err)
+	}
+}
@@ -0,0 +1,261 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/pkg/cache"
+
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+)
+
+func TestBannerRolloutAssignment(t *testing.T) {
+	previous := &entity.BannerSnapshot{Content: json.RawMessage(`{"title": "old"}`)}
+	banner := &entity.Banner{
+		ID:      1,
+		Content: entity.Content{Data: json.RawMessage(`{"title": "new"}`)},
+		Rollout: &entity.BannerRollout{Percent: 20, Previous: previous},
+	}
+
+	const users = 10000
+
+	inRollout := make(map[int]bool)
+
+	for userID := 1; userID <= users; userID++ {
+		shown := banner.ForUser(userID)
+		require.NotNil(t, shown)
+
+		if string(shown.Content.Data) == `{"title": "new"}` {
+			inRollout[userID] = true
+		} else {
+			require.JSONEq(t, `{"title": "old"}`, string(shown.Content.Data))
+		}
+	}
+
+	require.InDelta(t, 0.2, float64(len(inRollout))/users, 0.02)
+
+	// growing rollout keeps users who already see the new banner
+	banner.Rollout.Percent = 50
+
+	for userID := range inRollout {
+		require.JSONEq(t, `{"title": "new"}`, string(banner.ForUser(userID).Content.Data))
+	}
+
+	// without previous content users outside of rollout see nothing
+	banner.Rollout = &entity.BannerRollout{Percent: 0}
+	require.Nil(t, banner.ForUser(1))
+
+	banner.Rollout = nil
+	require.Same(t, banner, banner.ForUser(1))
+}
+
+func (s *Suite) TestBannerRolloutOnUpdate() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"rollout_tag"})
+	assertions.NoError(err)
+
+	tagIDs := []int{tags[0].ID}
+
+	created, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    tagIDs,
+		FeatureID: 1,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": "old"}`)},
+		Activity:  entity.Activity{IsActive: true},
+	})
+	assertions.NoError(err)
+
+	// cache the banner before rollout starts
+	banner, err := s.bannerService.GetBannerByFeatureAndTags(ctx, 1, tagIDs, false)
+	assertions.NoError(err)
+	assertions.Nil(banner.Rollout)
+
+	// replacing content starts rollout, current content is kept for the rest of users
+	err = s.bannerService.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		Content:  json.RawMessage(`{"title": "new"}`),
+		IsActive: ptr(true),
+		Rollout:  &entity.BannerRollout{Percent: 10},
+	})
+	assertions.NoError(err)
+
+	banner, err = s.bannerService.GetBannerByFeatureAndTags(ctx, 1, tagIDs, false)
+	assertions.NoError(err)
+	assertions.JSONEq(`{"title": "new"}`, string(banner.Content.Data))
+	assertions.Equal(10, banner.Rollout.Percent)
+	assertions.JSONEq(`{"title": "old"}`, string(banner.Rollout.Previous.Content))
+
+	// changing only the percent keeps previous content
+	err = s.bannerService.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		IsActive: ptr(true),
+		Rollout:  &entity.BannerRollout{Percent: 60},
+	})
+	assertions.NoError(err)
+
+	banner, err = s.bannerService.GetBannerByFeatureAndTags(ctx, 1, tagIDs, false)
+	assertions.NoError(err)
+	assertions.Equal(60, banner.Rollout.Percent)
+	assertions.JSONEq(`{"title": "old"}`, string(banner.Rollout.Previous.Content))
+
+	// full rollout drops previous content
+	err = s.bannerService.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		IsActive: ptr(true),
+		Rollout:  &entity.BannerRollout{Percent: entity.FullRollout},
+	})
+	assertions.NoError(err)
+
+	banner, err = s.bannerService.GetBannerByFeatureAndTags(ctx, 1, tagIDs, false)
+	assertions.NoError(err)
+	assertions.Nil(banner.Rollout)
+
+	_, err = s.bannerService.DeleteBanner(ctx, created.ID)
+	assertions.NoError(err)
+}
+
+// memoryBannerRepo keeps banners in memory and matches them exactly, other methods are not implemented
+type memoryBannerRepo struct {
+	bannerservice.BannerRepo
+
+	banners []*entity.Banner
+}
+
+func (r *memoryBannerRepo) GetBannerByID(_ context.Context, id int) (*entity.Banner, error) {
+	for _, banner := range r.banners {
+		if banner.ID == id {
+			copied := *banner
+			return &copied, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *memoryBannerRepo) GetBannerByFeatureAndTags(_ context.Context, featureID int, tagIDs []int) (*entity.Banner, error) {
+	for _, banner := range r.banners {
+		if banner.FeatureID == featureID && slices.Equal(banner.TagIDs, tagIDs) {
+			copied := *banner
+			return &copied, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *memoryBannerRepo) MatchBanner(ctx context.Context, featureID int, tagIDs []int, _ entity.BannerMatching) (*entity.Banner, error) {
+	if banner, _ := r.GetBannerByFeatureAndTags(ctx, featureID, tagIDs); banner != nil {
+		return banner, nil
+	}
+
+	for _, banner := range r.banners {
+		if banner.FeatureID == featureID && banner.IsDefault {
+			copied := *banner
+			return &copied, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *memoryBannerRepo) CreateBanner(_ context.Context, banner entity.Banner) (*entity.Banner, error) {
+	banner.ID = len(r.banners) + 1
+	r.banners = append(r.banners, &banner)
+
+	copied := banner
+
+	return &copied, nil
+}
+
+func (r *memoryBannerRepo) UpdateBanner(_ context.Context, id int, update entity.BannerUpdate) error {
+	for _, banner := range r.banners {
+		if banner.ID == id {
+			*banner = update.Apply(*banner)
+		}
+	}
+
+	return nil
+}
+
+func TestBannerRolloutKeepsLiveContent(t *testing.T) {
+	ctx := context.Background()
+
+	newService := func() (*bannerservice.Service, *memoryBannerRepo) {
+		repo := &memoryBannerRepo{}
+		service := bannerservice.New(
+			repo, schemaFeatureRepo{schema: json.RawMessage(`{}`)}, existingTagRepo{},
+			cache.NewInMem(time.Minute, time.Minute), bannerservice.CachePolicy{TTL: time.Minute},
+			entity.BannerMatchingExact, nil, nil, logrus.New(),
+		)
+
+		return service, repo
+	}
+
+	create := func(service *bannerservice.Service, tagIDs []int, title string, rollout *entity.BannerRollout, isDefault bool) *entity.Banner {
+		banner, err := service.CreateBanner(ctx, entity.Banner{
+			TagIDs:    tagIDs,
+			FeatureID: 1,
+			Content:   entity.Content{Data: json.RawMessage(`{"title": "` + title + `"}`)},
+			Activity:  entity.Activity{IsActive: true},
+			IsDefault: isDefault,
+			Rollout:   rollout,
+		})
+		require.NoError(t, err)
+
+		return banner
+	}
+
+	update := func(service *bannerservice.Service, id int, update entity.BannerUpdate) {
+		require.NoError(t, service.UpdateBanner(ctx, id, update))
+	}
+
+	// zero percent shows previous content to every user
+	shownOutside := func(repo *memoryBannerRepo, id int) string {
+		banner, err := repo.GetBannerByID(ctx, id)
+		require.NoError(t, err)
+
+		shown := banner.ForUser(1)
+		if shown == nil {
+			return ""
+		}
+
+		return string(shown.Content.Data)
+	}
+
+	// content is replaced first and rollout starts later
+	service, repo := newService()
+	banner := create(service, []int{1}, "old", nil, false)
+
+	update(service, banner.ID, entity.BannerUpdate{Content: json.RawMessage(`{"title": "new"}`)})
+	require.JSONEq(t, `{"title": "new"}`, shownOutside(repo, banner.ID))
+
+	update(service, banner.ID, entity.BannerUpdate{Rollout: &entity.BannerRollout{Percent: 0}})
+	require.JSONEq(t, `{"title": "old"}`, shownOutside(repo, banner.ID))
+
+	// finished rollout shows new content to everyone
+	update(service, banner.ID, entity.BannerUpdate{Rollout: &entity.BannerRollout{Percent: entity.FullRollout}})
+	require.JSONEq(t, `{"title": "new"}`, shownOutside(repo, banner.ID))
+
+	// rollout starts first and content is replaced later
+	service, repo = newService()
+	banner = create(service, []int{1}, "old", nil, false)
+
+	update(service, banner.ID, entity.BannerUpdate{Rollout: &entity.BannerRollout{Percent: 0}})
+	update(service, banner.ID, entity.BannerUpdate{Content: json.RawMessage(`{"title": "new"}`)})
+	require.JSONEq(t, `{"title": "old"}`, shownOutside(repo, banner.ID))
+
+	// content replaced again during rollout keeps the content shown before it started
+	update(service, banner.ID, entity.BannerUpdate{Content: json.RawMessage(`{"title": "newer"}`)})
+	require.JSONEq(t, `{"title": "old"}`, shownOutside(repo, banner.ID))
+
+	// banner created with partial rollout keeps showing default banner of the feature to the rest of users
+	service, repo = newService()
+	create(service, []int{1}, "default", nil, true)
+	banner = create(service, []int{2}, "new", &entity.BannerRollout{Percent: 0}, false)
+	require.JSONEq(t, `{"title": "default"}`, shownOutside(repo, banner.ID))
+}
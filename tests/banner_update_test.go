@@ -0,0 +1,209 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/internal/handler/mapper"
+	"avito-backend-trainee-2024/internal/handler/request"
+
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestUpdateBannerRequestActiveWindow(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	decode := func(body string) entity.BannerUpdate {
+		var req request.UpdateBannerRequest
+		require.NoError(t, json.Unmarshal([]byte(body), &req))
+
+		return mapper.MapUpdateBannerRequestToEntity(&req, 1)
+	}
+
+	// omitted bounds are kept
+	update := decode(`{"is_active": true}`)
+	require.Nil(t, update.ActiveFrom)
+	require.Nil(t, update.ActiveUntil)
+
+	// null removes the bound
+	update = decode(`{"active_from": "2024-05-01T00:00:00Z", "active_until": null}`)
+	require.NotNil(t, update.ActiveFrom)
+	require.True(t, from.Equal(*update.ActiveFrom.At))
+	require.NotNil(t, update.ActiveUntil)
+	require.Nil(t, update.ActiveUntil.At)
+
+	banner := entity.Banner{Activity: entity.Activity{ActiveUntil: &from}}
+	require.Nil(t, update.Apply(banner).ActiveUntil)
+	require.Equal(t, &from, entity.BannerUpdate{}.Apply(banner).ActiveUntil)
+}
+
+func (s *Suite) TestUpdateBannerKeepsActiveWindow() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"scheduled_tag"})
+	assertions.NoError(err)
+
+	from := time.Now().Add(time.Hour).Truncate(time.Second)
+	until := from.Add(24 * time.Hour)
+
+	created, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{tags[0].ID},
+		FeatureID: 1,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": "scheduled"}`)},
+		Activity:  entity.Activity{IsActive: true, ActiveFrom: &from, ActiveUntil: &until},
+	})
+	assertions.NoError(err)
+
+	err = s.bannerService.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		Content:  json.RawMessage(`{"title": "rescheduled"}`),
+		IsActive: ptr(true),
+	})
+	assertions.NoError(err)
+
+	banner, err := s.bannerRepo.GetBannerByID(ctx, created.ID)
+	assertions.NoError(err)
+	assertions.True(from.Equal(*banner.ActiveFrom))
+	assertions.True(until.Equal(*banner.ActiveUntil))
+
+	// new start after kept end makes window empty
+	later := until.Add(time.Hour)
+
+	err = s.bannerService.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		IsActive:   ptr(true),
+		ActiveFrom: &entity.TimeBound{At: &later},
+	})
+	assertions.ErrorIs(err, bannerservice.ErrInvalidActiveWindow)
+
+	err = s.bannerService.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		IsActive:    ptr(true),
+		ActiveUntil: &entity.TimeBound{},
+	})
+	assertions.NoError(err)
+
+	banner, err = s.bannerRepo.GetBannerByID(ctx, created.ID)
+	assertions.NoError(err)
+	assertions.True(from.Equal(*banner.ActiveFrom))
+	assertions.Nil(banner.ActiveUntil)
+
+	_, err = s.bannerService.DeleteBanner(ctx, created.ID)
+	assertions.NoError(err)
+}
+
+func TestBannerUpdateKeepsOmittedFields(t *testing.T) {
+	banner := entity.Banner{Priority: 5, IsDefault: true}
+
+	updated := entity.BannerUpdate{Content: json.RawMessage(`{"title": "new"}`)}.Apply(banner)
+	require.Equal(t, 5, updated.Priority)
+	require.True(t, updated.IsDefault)
+
+	updated = entity.BannerUpdate{Priority: ptr(0), IsDefault: ptr(false)}.Apply(banner)
+	require.Zero(t, updated.Priority)
+	require.False(t, updated.IsDefault)
+}
+
+func (s *Suite) TestUpdateDefaultBannerKeepsDefaultFlag() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	feature, err := s.featureRepo.CreateFeature(ctx, entity.Feature{Name: "updated_default_feature"})
+	assertions.NoError(err)
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"updated_default_tag"})
+	assertions.NoError(err)
+
+	created, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{tags[0].ID},
+		FeatureID: feature.ID,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": "default"}`)},
+		Activity:  entity.Activity{IsActive: true},
+		Priority:  3,
+		IsDefault: true,
+	})
+	assertions.NoError(err)
+
+	err = s.bannerService.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		Content:  json.RawMessage(`{"title": "edited default"}`),
+		IsActive: ptr(true),
+	})
+	assertions.NoError(err)
+
+	banner, err := s.bannerRepo.GetBannerByID(ctx, created.ID)
+	assertions.NoError(err)
+	assertions.Equal(3, banner.Priority)
+	assertions.True(banner.IsDefault)
+
+	_, err = s.featureRepo.DeleteFeature(ctx, feature.ID, true)
+	assertions.NoError(err)
+}
+
+func TestRolloutOnlyUpdateKeepsOtherFields(t *testing.T) {
+	var req request.UpdateBannerRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"rollout_percent": 30}`), &req))
+
+	update := mapper.MapUpdateBannerRequestToEntity(&req, 1)
+	require.Nil(t, update.IsActive)
+
+	from, until := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	banner := entity.Banner{
+		Activity:  entity.Activity{IsActive: true, ActiveFrom: &from, ActiveUntil: &until},
+		Priority:  7,
+		IsDefault: true,
+	}
+
+	updated := update.Apply(banner)
+	require.Equal(t, banner.Activity, updated.Activity)
+	require.Equal(t, 7, updated.Priority)
+	require.True(t, updated.IsDefault)
+	require.Equal(t, 30, updated.Rollout.Percent)
+}
+
+func (s *Suite) TestUpdateBannerRolloutOnly() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	feature, err := s.featureRepo.CreateFeature(ctx, entity.Feature{Name: "rollout_only_feature"})
+	assertions.NoError(err)
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"rollout_only_tag"})
+	assertions.NoError(err)
+
+	from := time.Now().Add(-time.Hour).Truncate(time.Second)
+	until := from.Add(24 * time.Hour)
+
+	created, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{tags[0].ID},
+		FeatureID: feature.ID,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": "rolled out"}`)},
+		Activity:  entity.Activity{IsActive: true, ActiveFrom: &from, ActiveUntil: &until},
+		Priority:  7,
+		IsDefault: true,
+	})
+	assertions.NoError(err)
+
+	err = s.bannerService.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		Rollout: &entity.BannerRollout{Percent: 30},
+	})
+	assertions.NoError(err)
+
+	banner, err := s.bannerRepo.GetBannerByID(ctx, created.ID)
+	assertions.NoError(err)
+	assertions.True(banner.IsActive)
+	assertions.True(from.Equal(*banner.ActiveFrom))
+	assertions.True(until.Equal(*banner.ActiveUntil))
+	assertions.Equal(7, banner.Priority)
+	assertions.True(banner.IsDefault)
+	assertions.Equal(30, banner.Rollout.Percent)
+
+	_, err = s.featureRepo.DeleteFeature(ctx, feature.ID, true)
+	assertions.NoError(err)
+}
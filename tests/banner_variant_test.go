@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+
+	userbannerhandler "avito-backend-trainee-2024/internal/handler/banner/user"
+	midlewares "avito-backend-trainee-2024/internal/handler/middleware"
+	jwtutils "avito-backend-trainee-2024/pkg/utils/jwt"
+)
+
+func TestBannerVariantAssignment(t *testing.T) {
+	banner := &entity.Banner{
+		ID: 1,
+		Variants: []entity.BannerVariant{
+			{Key: "a", Weight: 1, Content: json.RawMessage(`{"title": "a"}`)},
+			{Key: "b", Weight: 3, Content: json.RawMessage(`{"title": "b"}`)},
+		},
+	}
+
+	const users = 10000
+
+	counts := make(map[string]int)
+
+	for userID := 1; userID <= users; userID++ {
+		variant := banner.VariantFor(userID)
+		require.NotNil(t, variant)
+
+		// the same user always gets the same variant
+		require.Equal(t, variant.Key, banner.VariantFor(userID).Key)
+
+		counts[variant.Key]++
+	}
+
+	// shares follow weights
+	require.InDelta(t, 0.25, float64(counts["a"])/users, 0.03)
+	require.InDelta(t, 0.75, float64(counts["b"])/users, 0.03)
+
+	require.Nil(t, (&entity.Banner{ID: 1}).VariantFor(1))
+}
+
+func TestUserBannerVariantResponse(t *testing.T) {
+	const secret = "secret"
+
+	logger := logrus.New()
+	service := &revisionRecordingService{variants: []entity.BannerVariant{
+		{Key: "only", Weight: 1, Content: json.RawMessage(`{"title": "variant"}`)},
+	}}
+	handler := userbannerhandler.New(
+		service, userbannerhandler.CacheControl{}, logger, validator.New(),
+		midlewares.JWTAuthentication("token", secret, logger),
+	).Routes()
+
+	token, err := jwtutils.CreateJWT(
+		map[string]any{"id": 7, "username": "user", "is_admin": false}, jwt.SigningMethodHS256, secret,
+	)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/?feature_id=1&tag_ids=1", nil)
+	req.Header.Set("token", token)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "only", recorder.Header().Get(userbannerhandler.VariantHeader))
+	require.JSONEq(t, `{"title": "variant"}`, recorder.Body.String())
+}
+
+func (s *Suite) TestBannerVariantsRoundTrip() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	variants := []entity.BannerVariant{
+		{Key: "a", Weight: 1, Content: json.RawMessage(`{"title": "a"}`)},
+		{Key: "b", Weight: 2, Content: json.RawMessage(`{"title": "b"}`)},
+	}
+
+	created, err := s.bannerRepo.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{1},
+		FeatureID: 1,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": "title"}`)},
+		Variants:  variants,
+		Activity:  entity.Activity{IsActive: true},
+	})
+	assertions.NoError(err)
+
+	banner, err := s.bannerRepo.GetBannerByID(ctx, created.ID)
+	assertions.NoError(err)
+	assertions.Len(banner.Variants, len(variants))
+
+	for i, variant := range banner.Variants {
+		assertions.Equal(variants[i].Key, variant.Key)
+		assertions.Equal(variants[i].Weight, variant.Weight)
+		assertions.JSONEq(string(variants[i].Content), string(variant.Content))
+	}
+
+	// empty list removes variants
+	err = s.bannerRepo.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		Variants: []entity.BannerVariant{},
+		IsActive: ptr(true),
+	})
+	assertions.NoError(err)
+
+	banner, err = s.bannerRepo.GetBannerByID(ctx, created.ID)
+	assertions.NoError(err)
+	assertions.Empty(banner.Variants)
+
+	_, err = s.bannerRepo.DeleteBanner(ctx, created.ID)
+	assertions.NoError(err)
+}
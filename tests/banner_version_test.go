@@ -0,0 +1,243 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/pkg/cache"
+	"avito-backend-trainee-2024/pkg/errs"
+
+	bannerrepo "avito-backend-trainee-2024/internal/repository/postgres/banner"
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+)
+
+func (s *Suite) TestRestoreBannerVersion() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	created, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{2},
+		FeatureID: 2,
+		Content: entity.Content{
+			Data: json.RawMessage(`{"title": "first_title", "buttons": [{"text": "ok", "color": "#fff"}]}`),
+		},
+		Activity: entity.Activity{
+			IsActive: true,
+		},
+		Priority:  5,
+		IsDefault: true,
+		UpdatedBy: 2,
+	})
+	assertions.NoError(err)
+
+	err = s.bannerRepo.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		Content:   json.RawMessage(`{"title": "second_title"}`),
+		IsActive:  ptr(false),
+		Priority:  ptr(0),
+		IsDefault: ptr(false),
+		UpdatedBy: 2,
+	})
+	assertions.NoError(err)
+
+	versions, err := s.bannerRepo.GetBannerVersions(ctx, created.ID, 0, math.MaxInt64)
+	assertions.NoError(err)
+	assertions.Len(versions, 2)
+
+	assertions.Equal(2, versions[0].Version)
+	assertions.JSONEq(`{"title": "second_title"}`, string(versions[0].Content.Data))
+	assertions.False(versions[0].IsActive)
+	assertions.Zero(versions[0].Priority)
+	assertions.False(versions[0].IsDefault)
+	assertions.Equal(2, versions[0].CreatedBy)
+
+	assertions.Equal(1, versions[1].Version)
+	assertions.JSONEq(`{"title": "first_title", "buttons": [{"text": "ok", "color": "#fff"}]}`, string(versions[1].Content.Data))
+	assertions.True(versions[1].IsActive)
+	assertions.Equal(5, versions[1].Priority)
+	assertions.True(versions[1].IsDefault)
+
+	assertions.NoError(s.bannerRepo.RestoreBannerVersion(ctx, created.ID, 1, 2))
+
+	banner, err := s.bannerRepo.GetBannerByID(ctx, created.ID)
+	assertions.NoError(err)
+	assertions.JSONEq(`{"title": "first_title", "buttons": [{"text": "ok", "color": "#fff"}]}`, string(banner.Content.Data))
+	assertions.Equal([]int{2}, banner.TagIDs)
+	assertions.True(banner.IsActive)
+	assertions.Equal(5, banner.Priority)
+	assertions.True(banner.IsDefault)
+
+	versions, err = s.bannerRepo.GetBannerVersions(ctx, created.ID, 0, math.MaxInt64)
+	assertions.NoError(err)
+	assertions.Len(versions, 3)
+	assertions.JSONEq(string(versions[2].Content.Data), string(versions[0].Content.Data))
+
+	assertions.ErrorIs(s.bannerRepo.RestoreBannerVersion(ctx, created.ID, 10, 2), bannerrepo.ErrNoSuchBannerVersion)
+
+	_, err = s.bannerRepo.DeleteBanner(ctx, created.ID)
+	assertions.NoError(err)
+}
+
+func (s *Suite) TestConcurrentBannerUpdatesGetDistinctVersions() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	created, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{3},
+		FeatureID: 3,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": "title"}`)},
+		Activity:  entity.Activity{IsActive: true},
+	})
+	assertions.NoError(err)
+
+	const updates = 8
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, updates)
+
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			errs <- s.bannerRepo.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+				Content:  json.RawMessage(fmt.Sprintf(`{"title": "title %d"}`, i)),
+				IsActive: ptr(true),
+			})
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assertions.NoError(err)
+	}
+
+	versions, err := s.bannerRepo.GetBannerVersions(ctx, created.ID, 0, math.MaxInt64)
+	assertions.NoError(err)
+	assertions.Len(versions, updates+1)
+
+	for i, version := range versions {
+		assertions.Equal(updates+1-i, version.Version)
+	}
+
+	_, err = s.bannerRepo.DeleteBanner(ctx, created.ID)
+	assertions.NoError(err)
+}
+
+// versionedBannerRepo serves fixed versions of banners kept in memory and records restored ones
+type versionedBannerRepo struct {
+	memoryBannerRepo
+
+	versions []*entity.BannerVersion
+	restored []int
+}
+
+func (r *versionedBannerRepo) GetBannerVersion(_ context.Context, bannerID, version int) (*entity.BannerVersion, error) {
+	for _, v := range r.versions {
+		if v.BannerID == bannerID && v.Version == version {
+			return v, nil
+		}
+	}
+
+	return nil, bannerrepo.ErrNoSuchBannerVersion
+}
+
+func (r *versionedBannerRepo) RestoreBannerVersion(_ context.Context, _, version, _ int) error {
+	r.restored = append(r.restored, version)
+
+	return nil
+}
+
+// limitedReferenceRepo knows features and tags with ids below the limit, all features have title schema
+type limitedReferenceRepo struct {
+	limit int
+}
+
+func (r limitedReferenceRepo) GetFeatureByID(_ context.Context, id int) (*entity.Feature, error) {
+	if id >= r.limit {
+		return nil, sql.ErrNoRows
+	}
+
+	return &entity.Feature{ID: id, ContentSchema: json.RawMessage(titleSchema)}, nil
+}
+
+func (r limitedReferenceRepo) GetTagsWithIDs(_ context.Context, ids []int) ([]*entity.Tag, error) {
+	tags := make([]*entity.Tag, 0, len(ids))
+	for _, id := range ids {
+		if id < r.limit {
+			tags = append(tags, &entity.Tag{ID: id})
+		}
+	}
+
+	return tags, nil
+}
+
+func (r limitedReferenceRepo) GetTagByID(_ context.Context, id int) (*entity.Tag, error) {
+	if id >= r.limit {
+		return nil, sql.ErrNoRows
+	}
+
+	return &entity.Tag{ID: id}, nil
+}
+
+func TestRestoreBannerVersionValidatesVersion(t *testing.T) {
+	ctx := context.Background()
+
+	version := func(number, featureID, tagID int, content string) *entity.BannerVersion {
+		return &entity.BannerVersion{
+			BannerID:  1,
+			Version:   number,
+			FeatureID: featureID,
+			TagIDs:    []int{tagID},
+			Content:   entity.Content{Data: json.RawMessage(content)},
+		}
+	}
+
+	repo := &versionedBannerRepo{
+		memoryBannerRepo: memoryBannerRepo{banners: []*entity.Banner{{ID: 1, FeatureID: 1, TagIDs: []int{1}}}},
+		versions: []*entity.BannerVersion{
+			version(1, 1, 1, `{"title": "valid"}`),
+			version(2, 10, 1, `{"title": "deleted feature"}`),
+			version(3, 1, 10, `{"title": "deleted tag"}`),
+			version(4, 1, 1, `{"title": 1}`),
+		},
+	}
+	references := limitedReferenceRepo{limit: 10}
+	service := bannerservice.New(
+		repo, references, references, cache.NewInMem(time.Minute, time.Minute), bannerservice.CachePolicy{TTL: time.Minute},
+		entity.BannerMatchingExact, nil, nil, logrus.New(),
+	)
+
+	require.NoError(t, service.RestoreBannerVersion(ctx, 1, 1, 1))
+
+	err := service.RestoreBannerVersion(ctx, 1, 2, 1)
+	require.ErrorIs(t, err, bannerrepo.ErrVersionFeatureDeleted)
+	require.ErrorIs(t, err, errs.ErrConflict)
+
+	err = service.RestoreBannerVersion(ctx, 1, 3, 1)
+	require.ErrorIs(t, err, bannerrepo.ErrVersionTagDeleted)
+	require.ErrorIs(t, err, errs.ErrConflict)
+
+	// content must match current schema of the feature
+	err = service.RestoreBannerVersion(ctx, 1, 4, 1)
+	require.ErrorIs(t, err, errs.ErrInvalid)
+	require.Equal(t, []string{"/title"}, violationPaths(err))
+
+	require.ErrorIs(t, service.RestoreBannerVersion(ctx, 1, 5, 1), bannerrepo.ErrNoSuchBannerVersion)
+
+	// only valid version reached the database
+	require.Equal(t, []int{1}, repo.restored)
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/handler/response"
+
+	userbannerhandler "avito-backend-trainee-2024/internal/handler/banner/user"
+	midlewares "avito-backend-trainee-2024/internal/handler/middleware"
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+	jwtutils "avito-backend-trainee-2024/pkg/utils/jwt"
+)
+
+func TestBannerLookupBatchSharesCache(t *testing.T) {
+	repo := &countingBannerRepo{content: `{"title": "title"}`}
+	service := newLookupService(repo, bannerservice.CachePolicy{TTL: time.Minute}, nil)
+	ctx := context.Background()
+
+	_, err := service.GetBannerByFeatureAndTags(ctx, 2, []int{1}, false)
+	require.NoError(t, err)
+
+	results := service.LookupBanners(ctx, []int{3, 2, 3, 1}, []int{1}, false)
+
+	// duplicates are looked up once, banner of feature 2 is taken from cache
+	require.Len(t, results, 3)
+	require.Equal(t, int32(3), repo.calls.Load())
+
+	for i, featureID := range []int{3, 2, 1} {
+		require.Equal(t, featureID, results[i].FeatureID)
+		require.NoError(t, results[i].Err)
+		require.Equal(t, featureID, results[i].Lookup.Banner.FeatureID)
+	}
+}
+
+func TestUserBannerBatchEndpoint(t *testing.T) {
+	const secret = "secret"
+
+	logger := logrus.New()
+	handler := userbannerhandler.New(
+		&revisionRecordingService{missing: map[int]bool{2: true}}, userbannerhandler.CacheControl{}, logger, validator.New(),
+		midlewares.JWTAuthentication("token", secret, logger),
+	).Routes()
+
+	token, err := jwtutils.CreateJWT(
+		map[string]any{"id": 1, "username": "user", "is_admin": false}, jwt.SigningMethodHS256, secret,
+	)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/batch?feature_ids=1,2&tag_ids=1", nil)
+	req.Header.Set("token", token)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var resp response.GetUserBannersResponse
+
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+	require.Len(t, resp, 2)
+	require.JSONEq(t, `{"title": "title"}`, string(resp[1].Content))
+	require.Nil(t, resp[1].Error)
+	require.Empty(t, resp[2].Content)
+	require.Equal(t, &response.UserBannerError{Status: http.StatusNotFound, Code: "not_found", Detail: "no such banner"}, resp[2].Error)
+
+	req = httptest.NewRequest(http.MethodGet, "/batch?feature_ids=1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21&tag_ids=1", nil)
+	req.Header.Set("token", token)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/pkg/cache"
+)
+
+// TestCacheBackends checks that all cache backends have the same semantics, it doesn't need database
+func TestCacheBackends(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	lruCache, err := cache.NewLRU(100, time.Minute)
+	require.NoError(t, err)
+
+	backends := map[string]struct {
+		cache cache.Cache
+		// advance moves time of the backend forward
+		advance func(d time.Duration)
+	}{
+		"inmem": {
+			cache:   cache.NewInMem(time.Minute, time.Minute),
+			advance: time.Sleep,
+		},
+		"lru": {
+			cache:   lruCache,
+			advance: time.Sleep,
+		},
+		"redis": {
+			cache:   cache.NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}), "test:", time.Minute, logrus.New()),
+			advance: server.FastForward,
+		},
+	}
+
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := backend.cache
+
+			_, found := c.Get(ctx, "missing")
+			require.False(t, found)
+
+			c.Set(ctx, "key", []byte("value"), cache.DefaultExpiration)
+
+			val, found := c.Get(ctx, "key")
+			require.True(t, found)
+			require.Equal(t, []byte("value"), val)
+
+			c.Delete(ctx, "key")
+
+			_, found = c.Get(ctx, "key")
+			require.False(t, found)
+
+			// entry with explicit ttl expires
+			c.Set(ctx, "short", []byte("value"), 50*time.Millisecond)
+			c.Set(ctx, "forever", []byte("value"), cache.NoExpiration)
+
+			backend.advance(100 * time.Millisecond)
+
+			_, found = c.Get(ctx, "short")
+			require.False(t, found)
+
+			_, found = c.Get(ctx, "forever")
+			require.True(t, found)
+
+			c.Flush(ctx)
+
+			_, found = c.Get(ctx, "forever")
+			require.False(t, found)
+		})
+	}
+
+	// redis cache flushes only own keys
+	server.Set("other", "value")
+
+	backends["redis"].cache.Flush(context.Background())
+	require.True(t, server.Exists("other"))
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+
+	c, err := cache.NewLRU(2, cache.NoExpiration)
+	require.NoError(t, err)
+
+	c.Set(ctx, "first", []byte("1"), cache.DefaultExpiration)
+	c.Set(ctx, "second", []byte("2"), cache.DefaultExpiration)
+
+	// first becomes recently used, so second is evicted
+	_, found := c.Get(ctx, "first")
+	require.True(t, found)
+
+	c.Set(ctx, "third", []byte("3"), cache.DefaultExpiration)
+
+	_, found = c.Get(ctx, "second")
+	require.False(t, found)
+
+	_, found = c.Get(ctx, "first")
+	require.True(t, found)
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	userbannerhandler "avito-backend-trainee-2024/internal/handler/banner/user"
+	midlewares "avito-backend-trainee-2024/internal/handler/middleware"
+	jwtutils "avito-backend-trainee-2024/pkg/utils/jwt"
+)
+
+func TestUserBannerConditionalGet(t *testing.T) {
+	const secret = "secret"
+
+	logger := logrus.New()
+	cacheControl := userbannerhandler.CacheControl{
+		MaxAge:               5 * time.Minute,
+		StaleWhileRevalidate: time.Minute,
+		Vary:                 []string{"token"},
+	}
+	handler := userbannerhandler.New(
+		&revisionRecordingService{}, cacheControl, logger, validator.New(),
+		midlewares.JWTAuthentication("token", secret, logger),
+	).Routes()
+
+	token, err := jwtutils.CreateJWT(
+		map[string]any{"id": 1, "username": "user", "is_admin": false}, jwt.SigningMethodHS256, secret,
+	)
+	require.NoError(t, err)
+
+	get := func(query, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+		req.Header.Set("token", token)
+
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	recorder := get("feature_id=1&tag_ids=1", "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "public, max-age=300, stale-while-revalidate=60", recorder.Header().Get("Cache-Control"))
+	require.Equal(t, "token", recorder.Header().Get("Vary"))
+
+	etag := recorder.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	recorder = get("feature_id=1&tag_ids=1", `"other", W/`+etag)
+	require.Equal(t, http.StatusNotModified, recorder.Code)
+	require.Empty(t, recorder.Body.Bytes())
+	require.Equal(t, etag, recorder.Header().Get("ETag"))
+
+	recorder = get("feature_id=1&tag_ids=1", `"other"`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"title": "title"}`, recorder.Body.String())
+
+	// last revision must not be reused without revalidation
+	recorder = get("feature_id=1&tag_ids=1&use_last_revision=true", etag)
+	require.Equal(t, http.StatusNotModified, recorder.Code)
+	require.Equal(t, "public, no-cache", recorder.Header().Get("Cache-Control"))
+
+	// responses not varying by token must not be stored by shared caches
+	cacheControl.Vary = nil
+	handler = userbannerhandler.New(
+		&revisionRecordingService{}, cacheControl, logger, validator.New(),
+		midlewares.JWTAuthentication("token", secret, logger),
+	).Routes()
+
+	recorder = get("feature_id=1&tag_ids=1", "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "private, max-age=300, stale-while-revalidate=60", recorder.Header().Get("Cache-Control"))
+	require.Empty(t, recorder.Header().Get("Vary"))
+}
@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/pkg/cache"
+	"avito-backend-trainee-2024/pkg/errs"
+
+	adminbannerhandler "avito-backend-trainee-2024/internal/handler/banner/admin"
+	midlewares "avito-backend-trainee-2024/internal/handler/middleware"
+	featurerepo "avito-backend-trainee-2024/internal/repository/postgres/feature"
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+	featureservice "avito-backend-trainee-2024/internal/service/feature"
+	handlerutils "avito-backend-trainee-2024/pkg/utils/handler"
+	jwtutils "avito-backend-trainee-2024/pkg/utils/jwt"
+	schemautils "avito-backend-trainee-2024/pkg/utils/schema"
+)
+
+const titleSchema = `{
+	"type": "object",
+	"required": ["title"],
+	"properties": {
+		"title": {"type": "string"},
+		"buttons": {"type": "array", "items": {"type": "object", "required": ["text"]}}
+	}
+}`
+
+// violationPaths returns paths of schema violations in err, nil if err isn't a schema validation error
+func violationPaths(err error) []string {
+	var schemaErr *schemautils.ValidationError
+	if !errors.As(err, &schemaErr) {
+		return nil
+	}
+
+	paths := make([]string, 0, len(schemaErr.Violations))
+	for _, violation := range schemaErr.Violations {
+		paths = append(paths, violation.Path)
+	}
+
+	return paths
+}
+
+func TestContentSchemaValidation(t *testing.T) {
+	schema := json.RawMessage(titleSchema)
+
+	require.NoError(t, schemautils.Validate(schema, json.RawMessage(`{"title": "t", "buttons": [{"text": "ok"}]}`)))
+
+	err := schemautils.Validate(schema, json.RawMessage(`{"title": 1, "buttons": [{"text": "ok"}, {}]}`))
+	require.ErrorIs(t, err, errs.ErrInvalid)
+	require.ElementsMatch(t, []string{"/title", "/buttons/1"}, violationPaths(err))
+
+	// missing required property is reported at its parent
+	err = schemautils.Validate(schema, json.RawMessage(`{"buttons": [{}]}`))
+	require.ElementsMatch(t, []string{"", "/buttons/0"}, violationPaths(err))
+
+	// feature without schema has empty one, which allows any content
+	require.NoError(t, schemautils.Validate(json.RawMessage(`{}`), json.RawMessage(`{"anything": [1, "2"]}`)))
+
+	_, err = schemautils.Compile(json.RawMessage(`{"type": 1}`))
+	require.ErrorIs(t, err, schemautils.ErrInvalidSchema)
+}
+
+// schemaFeatureRepo returns features with the same content schema
+type schemaFeatureRepo struct {
+	schema json.RawMessage
+}
+
+func (r schemaFeatureRepo) GetFeatureByID(_ context.Context, id int) (*entity.Feature, error) {
+	return &entity.Feature{ID: id, ContentSchema: r.schema}, nil
+}
+
+// existingTagRepo reports every requested tag as existing
+type existingTagRepo struct{}
+
+func (existingTagRepo) GetTagsWithIDs(_ context.Context, ids []int) ([]*entity.Tag, error) {
+	tags := make([]*entity.Tag, 0, len(ids))
+	for _, id := range ids {
+		tags = append(tags, &entity.Tag{ID: id})
+	}
+
+	return tags, nil
+}
+
+func (existingTagRepo) GetTagByID(_ context.Context, id int) (*entity.Tag, error) {
+	return &entity.Tag{ID: id}, nil
+}
+
+func TestCreateBannerSchemaViolationsInProblem(t *testing.T) {
+	const secret = "secret"
+
+	logger := logrus.New()
+	valid := validator.New(validator.WithRequiredStructEnabled())
+	valid.RegisterTagNameFunc(handlerutils.JSONTagName)
+
+	service := bannerservice.New(
+		&countingBannerRepo{}, schemaFeatureRepo{schema: json.RawMessage(titleSchema)}, existingTagRepo{},
+		cache.NewInMem(time.Minute, time.Minute), bannerservice.CachePolicy{TTL: time.Minute},
+		entity.BannerMatchingExact, nil, nil, logger,
+	)
+	handler := adminbannerhandler.New(
+		service, nil, logger, valid,
+		midlewares.JWTAuthentication("token", secret, logger), midlewares.AdminAuthorization(logger),
+	).Routes()
+
+	token, err := jwtutils.CreateJWT(
+		map[string]any{"id": 1, "username": "admin", "is_admin": true}, jwt.SigningMethodHS256, secret,
+	)
+	require.NoError(t, err)
+
+	body := `{"tag_ids": [1], "feature_id": 1, "content": {"title": 1, "buttons": [{}]}, "is_active": true}`
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("token", token)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	var problem handlerutils.Problem
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+	fields := make([]string, 0, len(problem.Errors))
+	for _, fieldErr := range problem.Errors {
+		require.NotEmpty(t, fieldErr.Message)
+
+		fields = append(fields, fieldErr.Field)
+	}
+
+	require.ElementsMatch(t, []string{"/title", "/buttons/0"}, fields)
+}
+
+func (s *Suite) TestFeatureContentSchema() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	featureService := featureservice.New(featurerepo.New(s.db), s.bannerCache)
+
+	feature, err := featureService.CreateFeature(ctx, entity.Feature{Name: "schema_feature"})
+	assertions.NoError(err)
+
+	_, err = featureService.SetContentSchema(ctx, feature.ID, json.RawMessage(`{"type": 1}`))
+	assertions.ErrorIs(err, schemautils.ErrInvalidSchema)
+
+	feature, err = featureService.SetContentSchema(ctx, feature.ID, json.RawMessage(titleSchema))
+	assertions.NoError(err)
+	assertions.JSONEq(titleSchema, string(feature.ContentSchema))
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"schema_tag_1", "schema_tag_2"})
+	assertions.NoError(err)
+
+	created, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{tags[0].ID},
+		FeatureID: feature.ID,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": "valid", "buttons": [{"text": "ok"}]}`)},
+		Activity:  entity.Activity{IsActive: true},
+	})
+	assertions.NoError(err)
+
+	_, err = s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{tags[1].ID},
+		FeatureID: feature.ID,
+		Content:   entity.Content{Data: json.RawMessage(`{"buttons": [{}]}`)},
+	})
+	assertions.ElementsMatch([]string{"", "/buttons/0"}, violationPaths(err))
+
+	err = s.bannerService.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		Content:  json.RawMessage(`{"title": 1}`),
+		IsActive: ptr(true),
+	})
+	assertions.Equal([]string{"/title"}, violationPaths(err))
+
+	// content of feature without schema isn't restricted
+	other, err := featureService.CreateFeature(ctx, entity.Feature{Name: "schemaless_feature"})
+	assertions.NoError(err)
+
+	_, err = s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{tags[1].ID},
+		FeatureID: other.ID,
+		Content:   entity.Content{Data: json.RawMessage(`{"title": 1, "anything": [1, "2"]}`)},
+	})
+	assertions.NoError(err)
+
+	_, err = featureService.DeleteFeature(ctx, feature.ID, true)
+	assertions.NoError(err)
+
+	_, err = featureService.DeleteFeature(ctx, other.ID, true)
+	assertions.NoError(err)
+}
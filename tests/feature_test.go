@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+
+	featurerepo "avito-backend-trainee-2024/internal/repository/postgres/feature"
+)
+
+func (s *Suite) TestDeleteFeature() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	feature, err := s.featureRepo.CreateFeature(ctx, entity.Feature{Name: "feature_to_delete"})
+	assertions.NoError(err)
+	assertions.JSONEq(`{}`, string(feature.ContentSchema))
+
+	banner, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{1},
+		FeatureID: feature.ID,
+		Content: entity.Content{
+			Data: json.RawMessage(`{"title": "some_title"}`),
+		},
+		Activity: entity.Activity{
+			IsActive: true,
+		},
+	})
+	assertions.NoError(err)
+
+	_, err = s.featureRepo.DeleteFeature(ctx, feature.ID, false)
+	assertions.ErrorIs(err, featurerepo.ErrFeatureHasBanners)
+
+	existing, err := s.bannerRepo.GetBannerByID(ctx, banner.ID)
+	assertions.NoError(err)
+	assertions.NotNil(existing)
+
+	deleted, err := s.featureRepo.DeleteFeature(ctx, feature.ID, true)
+	assertions.NoError(err)
+	assertions.Equal(feature.ID, deleted.ID)
+
+	existing, err = s.bannerRepo.GetBannerByID(ctx, banner.ID)
+	assertions.NoError(err)
+	assertions.Nil(existing)
+}
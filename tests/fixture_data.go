@@ -1,9 +1,14 @@
 package tests
 
 import (
+	"encoding/json"
+	"time"
+
 	"avito-backend-trainee-2024/internal/domain/entity"
 )
 
+var expiredAt = time.Now().Add(-time.Hour)
+
 var (
 	users = []entity.User{
 		{
@@ -23,21 +28,32 @@ var (
 			TagIDs:    []int{1, 2},
 			FeatureID: 1,
 			Content: entity.Content{
-				Title: "title",
-				Text:  "text",
-				Url:   "http://url.com",
+				Data: json.RawMessage(`{"title": "title", "text": "text", "url": "http://url.com"}`),
+			},
+			Activity: entity.Activity{
+				IsActive: true,
 			},
-			IsActive: true,
 		},
 		{
 			TagIDs:    []int{1},
 			FeatureID: 2,
 			Content: entity.Content{
-				Title: "title2",
-				Text:  "text2",
-				Url:   "http://url2.com",
+				Data: json.RawMessage(`{"title": "title2", "text": "text2", "url": "http://url2.com"}`),
+			},
+			Activity: entity.Activity{
+				IsActive: false,
+			},
+		},
+		{
+			TagIDs:    []int{2},
+			FeatureID: 1,
+			Content: entity.Content{
+				Data: json.RawMessage(`{"title": "title3", "text": "text3", "url": "http://url3.com"}`),
+			},
+			Activity: entity.Activity{
+				IsActive:    true,
+				ActiveUntil: &expiredAt,
 			},
-			IsActive: false,
 		},
 	}
 )
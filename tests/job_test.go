@@ -0,0 +1,247 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/internal/handler/response"
+
+	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+	jobservice "avito-backend-trainee-2024/internal/service/job"
+	jwtutils "avito-backend-trainee-2024/pkg/utils/jwt"
+)
+
+func (s *Suite) adminToken() string {
+	token, err := jwtutils.CreateJWT(
+		map[string]any{"id": 1, "username": "admin", "is_admin": true}, jwt.SigningMethodHS256, jwtSecret,
+	)
+	s.Require().NoError(err)
+
+	return token
+}
+
+// runDeleteBannersJob enqueues deletion of banners with provided query via API and waits until job is finished
+func (s *Suite) runDeleteBannersJob(query string) response.GetJobResponse {
+	assertions := s.Require()
+	token := s.adminToken()
+
+	req := httptest.NewRequest(http.MethodDelete, "/?"+query, nil)
+	req.Header.Set("token", token)
+
+	recorder := httptest.NewRecorder()
+	s.adminBannerHandler.Routes().ServeHTTP(recorder, req)
+	assertions.Equal(http.StatusAccepted, recorder.Code)
+
+	var created response.CreateJobResponse
+	assertions.NoError(json.Unmarshal(recorder.Body.Bytes(), &created))
+
+	var job response.GetJobResponse
+
+	assertions.Eventually(func() bool {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/%d", created.ID), nil)
+		req.Header.Set("token", token)
+
+		recorder := httptest.NewRecorder()
+		s.jobHandler.Routes().ServeHTTP(recorder, req)
+
+		if recorder.Code != http.StatusOK || json.Unmarshal(recorder.Body.Bytes(), &job) != nil {
+			return false
+		}
+
+		return job.Status != entity.JobStatusPending && job.Status != entity.JobStatusRunning
+	}, 10*time.Second, 50*time.Millisecond)
+
+	return job
+}
+
+func (s *Suite) TestDeleteBannersJob() {
+	assertions := s.Require()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		s.jobService.Run(ctx)
+	}()
+
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	feature, err := s.featureRepo.CreateFeature(ctx, entity.Feature{Name: "deleted_by_job_feature"})
+	assertions.NoError(err)
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"deleted_by_job_tag_1", "deleted_by_job_tag_2"})
+	assertions.NoError(err)
+
+	for _, tag := range tags {
+		_, err = s.bannerService.CreateBanner(ctx, entity.Banner{
+			TagIDs:    []int{tag.ID},
+			FeatureID: feature.ID,
+			Content:   entity.Content{Data: json.RawMessage(`{"title": "deleted"}`)},
+			Activity:  entity.Activity{IsActive: true},
+		})
+		assertions.NoError(err)
+
+		// banner is cached, job must flush it
+		_, err = s.bannerService.GetBannerByFeatureAndTags(ctx, feature.ID, []int{tag.ID}, false)
+		assertions.NoError(err)
+	}
+
+	job := s.runDeleteBannersJob(fmt.Sprintf("tag_id=%d", tags[0].ID))
+	assertions.Equal(entity.JobStatusDone, job.Status)
+	assertions.Equal(1, job.Deleted)
+
+	_, err = s.bannerService.GetBannerByFeatureAndTags(ctx, feature.ID, []int{tags[0].ID}, false)
+	assertions.ErrorIs(err, bannerservice.ErrNoSuchBanner)
+
+	job = s.runDeleteBannersJob(fmt.Sprintf("feature_id=%d", feature.ID))
+	assertions.Equal(entity.JobStatusDone, job.Status)
+	assertions.Equal(1, job.Deleted)
+
+	_, err = s.bannerService.GetBannerByFeatureAndTags(ctx, feature.ID, []int{tags[1].ID}, false)
+	assertions.ErrorIs(err, bannerservice.ErrNoSuchBanner)
+}
+
+func (s *Suite) TestClaimJobLeftRunning() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	created, err := s.jobRepo.CreateJob(ctx, entity.Job{FeatureID: 1_000_000})
+	assertions.NoError(err)
+
+	claim := func() *entity.Job {
+		for {
+			job, err := s.jobRepo.ClaimPendingJob(ctx, time.Minute)
+			assertions.NoError(err)
+
+			if job == nil || job.ID == created.ID {
+				return job
+			}
+		}
+	}
+
+	job := claim()
+	assertions.NotNil(job)
+	assertions.Equal(entity.JobStatusRunning, job.Status)
+
+	// running job isn't claimed again while its lease isn't expired
+	assertions.Nil(claim())
+
+	// instance running the job crashed and didn't extend the lease
+	_, err = s.db.ExecContext(ctx, "UPDATE job SET updated_at = now() - interval '1 hour' WHERE id = $1", created.ID)
+	assertions.NoError(err)
+
+	job = claim()
+	assertions.NotNil(job)
+	assertions.Equal(created.ID, job.ID)
+}
+
+type leaseRecordingJobRepo struct {
+	mu       sync.Mutex
+	pending  []*entity.Job
+	finished map[int]entity.JobStatus
+	extended atomic.Int64
+}
+
+func (r *leaseRecordingJobRepo) CreateJob(_ context.Context, job entity.Job) (*entity.Job, error) {
+	return &job, nil
+}
+
+func (r *leaseRecordingJobRepo) GetJobByID(_ context.Context, id int) (*entity.Job, error) {
+	return &entity.Job{ID: id}, nil
+}
+
+func (r *leaseRecordingJobRepo) ClaimPendingJob(_ context.Context, _ time.Duration) (*entity.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) == 0 {
+		return nil, nil
+	}
+
+	job := r.pending[0]
+	r.pending = r.pending[1:]
+
+	return job, nil
+}
+
+func (r *leaseRecordingJobRepo) ExtendJobLease(_ context.Context, _ int) error {
+	r.extended.Add(1)
+
+	return nil
+}
+
+func (r *leaseRecordingJobRepo) FinishJob(_ context.Context, id int, status entity.JobStatus, _ int, _ string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.finished[id] = status
+
+	return nil
+}
+
+func (r *leaseRecordingJobRepo) finishedStatus(id int) entity.JobStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.finished[id]
+}
+
+type slowBannerDeleter struct{}
+
+func (slowBannerDeleter) DeleteBanners(ctx context.Context, _, _ int) (int, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-time.After(200 * time.Millisecond):
+		return 3, nil
+	}
+}
+
+type flushCountingCache struct {
+	flushed atomic.Int64
+}
+
+func (c *flushCountingCache) Flush(_ context.Context) {
+	c.flushed.Add(1)
+}
+
+func TestJobLeaseExtendedWhileRunning(t *testing.T) {
+	jobRepo := &leaseRecordingJobRepo{
+		pending:  []*entity.Job{{ID: 1, FeatureID: 1}},
+		finished: make(map[int]entity.JobStatus),
+	}
+	cache := &flushCountingCache{}
+
+	service := jobservice.New(jobRepo, slowBannerDeleter{}, cache, time.Hour, 30*time.Millisecond, logrus.New())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go service.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		return jobRepo.finishedStatus(1) == entity.JobStatusDone
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Positive(t, jobRepo.extended.Load())
+	require.Equal(t, int64(1), cache.flushed.Load())
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+
+	pgutils "avito-backend-trainee-2024/pkg/utils/postgres"
+)
+
+func TestIntArrayScan(t *testing.T) {
+	cases := []struct {
+		src  any
+		want []int
+	}{
+		{src: "{}", want: []int{}},
+		{src: nil, want: []int{}},
+		{src: "{1}", want: []int{1}},
+		{src: []byte("{1,2,3}"), want: []int{1, 2, 3}},
+	}
+
+	for _, c := range cases {
+		var ints pgutils.IntArray
+
+		require.NoError(t, ints.Scan(c.src))
+		require.Equal(t, c.want, []int(ints))
+	}
+
+	var ints pgutils.IntArray
+	require.Error(t, ints.Scan("{1,a}"))
+}
+
+func (s *Suite) TestBannerWithoutTagsRoundTrip() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	created, err := s.bannerRepo.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{},
+		FeatureID: 1,
+		Content: entity.Content{
+			Data: json.RawMessage(`{"title": "no tags"}`),
+		},
+		Activity: entity.Activity{
+			IsActive: true,
+		},
+	})
+	assertions.NoError(err)
+
+	banner, err := s.bannerRepo.GetBannerByID(ctx, created.ID)
+	assertions.NoError(err)
+	assertions.Empty(banner.TagIDs)
+
+	version, err := s.bannerRepo.GetBannerVersion(ctx, created.ID, 1)
+	assertions.NoError(err)
+	assertions.Empty(version.TagIDs)
+
+	deleted, err := s.bannerRepo.DeleteBanner(ctx, created.ID)
+	assertions.NoError(err)
+	assertions.Empty(deleted.TagIDs)
+}
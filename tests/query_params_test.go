@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+)
+
+func (s *Suite) TestSpecialCharactersRoundTrip() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	names := []string{`it's a "tag"`, "тег 🏷", "'; DROP TABLE tag; --"}
+
+	tags, err := s.tagRepo.CreateTags(ctx, names)
+	assertions.NoError(err)
+	assertions.Len(tags, len(names))
+
+	tagIDs := make([]int, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	gotTags, err := s.tagRepo.GetTagsWithIDs(ctx, tagIDs)
+	assertions.NoError(err)
+	assertions.Len(gotTags, len(names))
+
+	for i, tag := range gotTags {
+		assertions.Equal(names[i], tag.Name)
+	}
+
+	content := `{"title": "it's \"quoted\"", "text": "Привет, мир 👋", "url": "'); DROP TABLE banner; --"}`
+
+	created, err := s.bannerRepo.CreateBanner(ctx, entity.Banner{
+		TagIDs:    tagIDs,
+		FeatureID: 1,
+		Content: entity.Content{
+			Data: json.RawMessage(content),
+		},
+		Activity: entity.Activity{
+			IsActive: true,
+		},
+	})
+	assertions.NoError(err)
+
+	banner, err := s.bannerRepo.GetBannerByID(ctx, created.ID)
+	assertions.NoError(err)
+	assertions.JSONEq(content, string(banner.Content.Data))
+
+	updatedContent := `{"title": "O'Reilly", "text": "\\x00 $1 %v 1' OR '1'='1", "emoji": "✅"}`
+
+	err = s.bannerRepo.UpdateBanner(ctx, created.ID, entity.BannerUpdate{
+		TagIDs:   tagIDs[:2],
+		Content:  json.RawMessage(updatedContent),
+		IsActive: ptr(true),
+	})
+	assertions.NoError(err)
+
+	banners, err := s.bannerRepo.GetBannersWithFeatureAndTag(ctx, 1, tagIDs[0], entity.BannerStatusLive, 0, math.MaxInt64)
+	assertions.NoError(err)
+	assertions.Len(banners, 1)
+	assertions.Equal(created.ID, banners[0].ID)
+	assertions.Equal(tagIDs[:2], banners[0].TagIDs)
+	assertions.JSONEq(updatedContent, string(banners[0].Content.Data))
+
+	_, err = s.bannerRepo.DeleteBanner(ctx, created.ID)
+	assertions.NoError(err)
+}
@@ -14,36 +14,79 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
 
-	gocache "github.com/patrickmn/go-cache"
-
 	"avito-backend-trainee-2024/internal/domain/entity"
+	"avito-backend-trainee-2024/pkg/cache"
 	"avito-backend-trainee-2024/pkg/hasher"
 
+	handlerutils "avito-backend-trainee-2024/pkg/utils/handler"
+
+	adminbannerhandler "avito-backend-trainee-2024/internal/handler/banner/admin"
 	userbannerhandler "avito-backend-trainee-2024/internal/handler/banner/user"
+	jobhandler "avito-backend-trainee-2024/internal/handler/job"
 	midlewares "avito-backend-trainee-2024/internal/handler/middleware"
 	bannerrepo "avito-backend-trainee-2024/internal/repository/postgres/banner"
 	featurerepo "avito-backend-trainee-2024/internal/repository/postgres/feature"
+	jobrepo "avito-backend-trainee-2024/internal/repository/postgres/job"
 	tagrepo "avito-backend-trainee-2024/internal/repository/postgres/tag"
 	userrepo "avito-backend-trainee-2024/internal/repository/postgres/user"
 	authservice "avito-backend-trainee-2024/internal/service/auth"
 	bannerservice "avito-backend-trainee-2024/internal/service/banner"
+	jobservice "avito-backend-trainee-2024/internal/service/job"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
 type BannerService interface {
-	GetBannerByFeatureAndTags(ctx context.Context, featureID int, tagIDs []int) (*entity.Banner, error)
+	GetBannerByFeatureAndTags(ctx context.Context, featureID int, tagIDs []int, useLastRevision bool) (*entity.Banner, error)
+	LookupBanner(ctx context.Context, featureID int, tagIDs []int, useLastRevision bool) (*bannerservice.BannerLookup, error)
+	LookupBanners(ctx context.Context, featureIDs []int, tagIDs []int, useLastRevision bool) []bannerservice.BatchLookup
 	CreateBanner(ctx context.Context, banner entity.Banner) (*entity.Banner, error)
+	UpdateBanner(ctx context.Context, id int, update entity.BannerUpdate) error
+	DeleteBanner(ctx context.Context, id int) (*entity.Banner, error)
+	GetAllBanners(ctx context.Context, status entity.BannerStatus, offset, limit int) ([]*entity.Banner, error)
+	GetBannersWithFeatureAndTag(ctx context.Context, featureID, tagID int, status entity.BannerStatus, offset, limit int) ([]*entity.Banner, error)
+	GetBannerVersions(ctx context.Context, id int, offset, limit int) ([]*entity.BannerVersion, error)
+	RestoreBannerVersion(ctx context.Context, id, version, restoredBy int) error
+	CacheStats() entity.CacheStats
+}
+
+type JobService interface {
+	CreateDeleteBannersJob(ctx context.Context, featureID, tagID int) (*entity.Job, error)
+	GetJobByID(ctx context.Context, id int) (*entity.Job, error)
+	Run(ctx context.Context)
+}
+
+type JobRepo interface {
+	CreateJob(ctx context.Context, job entity.Job) (*entity.Job, error)
+	ClaimPendingJob(ctx context.Context, leaseTimeout time.Duration) (*entity.Job, error)
 }
 
 type BannerRepo interface {
-	GetAllBanners(ctx context.Context, offset, limit int) ([]*entity.Banner, error)
-	GetBannersWithFeatureAndTag(ctx context.Context, featureID, tagID int, offset, limit int) ([]*entity.Banner, error)
+	GetAllBanners(ctx context.Context, status entity.BannerStatus, offset, limit int) ([]*entity.Banner, error)
+	GetBannersWithFeatureAndTag(ctx context.Context, featureID, tagID int, status entity.BannerStatus, offset, limit int) ([]*entity.Banner, error)
 	GetBannerByID(ctx context.Context, id int) (*entity.Banner, error)
 	GetBannerByFeatureAndTags(ctx context.Context, featureID int, tagIDs []int) (*entity.Banner, error)
+	MatchBanner(ctx context.Context, featureID int, tagIDs []int, matching entity.BannerMatching) (*entity.Banner, error)
 	CreateBanner(ctx context.Context, banner entity.Banner) (*entity.Banner, error)
-	UpdateBanner(ctx context.Context, id int, updateModel entity.Banner) error
+	UpdateBanner(ctx context.Context, id int, update entity.BannerUpdate) error
 	DeleteBanner(ctx context.Context, id int) (*entity.Banner, error)
+	GetBannerVersions(ctx context.Context, bannerID int, offset, limit int) ([]*entity.BannerVersion, error)
+	GetBannerVersion(ctx context.Context, bannerID, version int) (*entity.BannerVersion, error)
+	RestoreBannerVersion(ctx context.Context, bannerID, version, restoredBy int) error
+	ListenBannerChanges(ctx context.Context, onListen func(), onChange func(change entity.BannerChange)) error
+}
+
+type FeatureRepo interface {
+	GetFeatureByID(ctx context.Context, id int) (*entity.Feature, error)
+	CreateFeature(ctx context.Context, feature entity.Feature) (*entity.Feature, error)
+	DeleteFeature(ctx context.Context, id int, cascade bool) (*entity.Feature, error)
+}
+
+type TagRepo interface {
+	GetTagByID(ctx context.Context, id int) (*entity.Tag, error)
+	GetTagsWithIDs(ctx context.Context, IDs []int) ([]*entity.Tag, error)
+	CreateTags(ctx context.Context, names []string) ([]*entity.Tag, error)
+	DeleteTag(ctx context.Context, id int, cascade bool) (*entity.Tag, error)
 }
 
 type BannerHandler interface {
@@ -51,6 +94,10 @@ type BannerHandler interface {
 	Routes() *chi.Mux
 }
 
+type Router interface {
+	Routes() *chi.Mux
+}
+
 var (
 	dbConnectionStr string
 	jwtSecret       string
@@ -67,8 +114,16 @@ type Suite struct {
 	db *sqlx.DB
 
 	bannerRepo    BannerRepo
+	featureRepo   FeatureRepo
+	tagRepo       TagRepo
+	jobRepo       JobRepo
+	bannerCache   *cache.InMem
 	bannerService BannerService
+	jobService    JobService
 	bannerHandler BannerHandler
+
+	adminBannerHandler Router
+	jobHandler         Router
 }
 
 func TestSuite(t *testing.T) {
@@ -86,24 +141,41 @@ func (s *Suite) setupDB() {
 
 func (s *Suite) setupRepos() {
 	s.bannerRepo = bannerrepo.New(s.db)
+	s.featureRepo = featurerepo.New(s.db)
+	s.tagRepo = tagrepo.New(s.db)
+	s.jobRepo = jobrepo.New(s.db)
 }
 
 func (s *Suite) setupServices() {
-	featureRepo := featurerepo.New(s.db)
-	tagRepo := tagrepo.New(s.db)
+	s.bannerCache = cache.NewInMem(5*time.Minute, 10*time.Minute)
+
+	cachePolicy := bannerservice.CachePolicy{
+		TTL:         5 * time.Minute,
+		NegativeTTL: time.Minute,
+	}
+
+	s.bannerService = bannerservice.New(
+		s.bannerRepo, s.featureRepo, s.tagRepo, s.bannerCache, cachePolicy, entity.BannerMatchingExact, nil, nil, logrus.New(),
+	)
 
-	s.bannerService = bannerservice.New(s.bannerRepo, featureRepo, tagRepo)
+	s.jobService = jobservice.New(
+		jobrepo.New(s.db), bannerrepo.New(s.db), s.bannerCache, 100*time.Millisecond, time.Minute, logrus.New(),
+	)
 }
 
 func (s *Suite) setupHandlers() {
 	logger := logrus.New()
 	valid := validator.New(validator.WithRequiredStructEnabled())
-	cache := gocache.New(5*time.Minute, 10*time.Minute)
+	valid.RegisterTagNameFunc(handlerutils.JSONTagName)
 
 	authMiddleware := midlewares.JWTAuthentication("token", jwtSecret, logger)
-	cacheMiddleware := midlewares.InMemUserBannerCache(cache, logger)
+	adminAuthMiddleware := midlewares.AdminAuthorization(logger)
 
-	s.bannerHandler = userbannerhandler.New(s.bannerService, logger, valid, authMiddleware, cacheMiddleware)
+	s.bannerHandler = userbannerhandler.New(s.bannerService, userbannerhandler.CacheControl{}, logger, valid, authMiddleware)
+	s.adminBannerHandler = adminbannerhandler.New(
+		s.bannerService, s.jobService, logger, valid, authMiddleware, adminAuthMiddleware,
+	)
+	s.jobHandler = jobhandler.New(s.jobService, logger, authMiddleware, adminAuthMiddleware)
 }
 
 func (s *Suite) SetupSuite() {
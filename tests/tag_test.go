@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+
+	"avito-backend-trainee-2024/internal/domain/entity"
+
+	tagrepo "avito-backend-trainee-2024/internal/repository/postgres/tag"
+)
+
+func (s *Suite) TestDeleteTag() {
+	assertions := s.Require()
+	ctx := context.Background()
+
+	tags, err := s.tagRepo.CreateTags(ctx, []string{"tag_to_delete", "tag_to_keep"})
+	assertions.NoError(err)
+	assertions.Len(tags, 2)
+
+	banner, err := s.bannerService.CreateBanner(ctx, entity.Banner{
+		TagIDs:    []int{tags[0].ID},
+		FeatureID: 1,
+		Content: entity.Content{
+			Data: json.RawMessage(`{"title": "some_title"}`),
+		},
+		Activity: entity.Activity{
+			IsActive: true,
+		},
+	})
+	assertions.NoError(err)
+
+	_, err = s.tagRepo.DeleteTag(ctx, tags[0].ID, false)
+	assertions.ErrorIs(err, tagrepo.ErrTagHasBanners)
+
+	deleted, err := s.tagRepo.DeleteTag(ctx, tags[1].ID, false)
+	assertions.NoError(err)
+	assertions.Equal("tag_to_keep", deleted.Name)
+
+	_, err = s.tagRepo.DeleteTag(ctx, tags[0].ID, true)
+	assertions.NoError(err)
+
+	existing, err := s.bannerRepo.GetBannerByID(ctx, banner.ID)
+	assertions.NoError(err)
+	assertions.Nil(existing)
+}
@@ -8,9 +8,8 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
 
-	"avito-backend-trainee-2024/internal/domain/entity"
-
 	router "avito-backend-trainee-2024/pkg/route"
+	handlerutils "avito-backend-trainee-2024/pkg/utils/handler"
 	jwtutils "avito-backend-trainee-2024/pkg/utils/jwt"
 )
 
@@ -48,11 +47,15 @@ func (s *Suite) TestGetNotExistingBannerByUser() {
 	recorder := httptest.NewRecorder()
 	r.ServeHTTP(recorder, req)
 
-	assertions.Equal(http.StatusBadRequest, recorder.Result().StatusCode)
+	assertions.Equal(http.StatusNotFound, recorder.Result().StatusCode)
+
+	var problem handlerutils.Problem
 
-	respMsg := recorder.Body.String()
+	s.NoError(json.NewDecoder(recorder.Body).Decode(&problem))
 
-	assertions.Equal("error occurred fetching banner: no such banner", respMsg)
+	assertions.Equal("application/problem+json", recorder.Header().Get("Content-Type"))
+	assertions.Equal("not_found", problem.Code)
+	assertions.Equal("no such banner", problem.Detail)
 }
 
 func (s *Suite) TestGetActiveBannerByUser() {
@@ -91,13 +94,13 @@ func (s *Suite) TestGetActiveBannerByUser() {
 
 	assertions.Equal(http.StatusOK, recorder.Result().StatusCode)
 
-	var banner entity.Banner
+	var content map[string]any
 
-	s.NoError(json.NewDecoder(recorder.Body).Decode(&banner))
+	s.NoError(json.NewDecoder(recorder.Body).Decode(&content))
 
-	assertions.Equal("title", banner.Content.Title)
-	assertions.Equal("text", banner.Content.Text)
-	assertions.Equal("http://url.com", banner.Content.Url)
+	assertions.Equal("title", content["title"])
+	assertions.Equal("text", content["text"])
+	assertions.Equal("http://url.com", content["url"])
 }
 
 func (s *Suite) TestGetNotExistingBannerByAdmin() {
@@ -134,11 +137,15 @@ func (s *Suite) TestGetNotExistingBannerByAdmin() {
 	recorder := httptest.NewRecorder()
 	r.ServeHTTP(recorder, req)
 
-	assertions.Equal(http.StatusBadRequest, recorder.Result().StatusCode)
+	assertions.Equal(http.StatusNotFound, recorder.Result().StatusCode)
+
+	var problem handlerutils.Problem
 
-	respMsg := recorder.Body.String()
+	s.NoError(json.NewDecoder(recorder.Body).Decode(&problem))
 
-	assertions.Equal("error occurred fetching banner: no such banner", respMsg)
+	assertions.Equal("application/problem+json", recorder.Header().Get("Content-Type"))
+	assertions.Equal("not_found", problem.Code)
+	assertions.Equal("no such banner", problem.Detail)
 }
 
 func (s *Suite) TestGetActiveBannerByAdmin() {
@@ -177,13 +184,13 @@ func (s *Suite) TestGetActiveBannerByAdmin() {
 
 	assertions.Equal(http.StatusOK, recorder.Result().StatusCode)
 
-	var banner entity.Banner
+	var content map[string]any
 
-	s.NoError(json.NewDecoder(recorder.Body).Decode(&banner))
+	s.NoError(json.NewDecoder(recorder.Body).Decode(&content))
 
-	assertions.Equal("title", banner.Content.Title)
-	assertions.Equal("text", banner.Content.Text)
-	assertions.Equal("http://url.com", banner.Content.Url)
+	assertions.Equal("title", content["title"])
+	assertions.Equal("text", content["text"])
+	assertions.Equal("http://url.com", content["url"])
 }
 
 func (s *Suite) TestGetInactiveBannerByUser() {
@@ -222,9 +229,13 @@ func (s *Suite) TestGetInactiveBannerByUser() {
 
 	assertions.Equal(http.StatusForbidden, recorder.Result().StatusCode)
 
-	respMsg := recorder.Body.String()
+	var problem handlerutils.Problem
 
-	assertions.Equal("banner is inactive", respMsg)
+	s.NoError(json.NewDecoder(recorder.Body).Decode(&problem))
+
+	assertions.Equal("application/problem+json", recorder.Header().Get("Content-Type"))
+	assertions.Equal("forbidden", problem.Code)
+	assertions.Equal("banner is inactive", problem.Detail)
 }
 
 func (s *Suite) TestGetInactiveBannerByAdmin() {
@@ -263,11 +274,56 @@ func (s *Suite) TestGetInactiveBannerByAdmin() {
 
 	assertions.Equal(http.StatusOK, recorder.Result().StatusCode)
 
-	var banner entity.Banner
+	var content map[string]any
+
+	s.NoError(json.NewDecoder(recorder.Body).Decode(&content))
+
+	assertions.Equal("title2", content["title"])
+	assertions.Equal("text2", content["text"])
+	assertions.Equal("http://url2.com", content["url"])
+}
+
+func (s *Suite) TestGetExpiredBannerByUser() {
+	assertions := s.Require()
+
+	req, _ := http.NewRequest("GET", "/test/api/user_banner", nil)
+
+	payload := map[string]any{ // this user should exist in db
+		"id":       1,
+		"username": "user",
+		"is_admin": false,
+	}
+
+	token, err := jwtutils.CreateJWT(payload, jwt.SigningMethodHS256, jwtSecret)
+	s.NoError(err)
+
+	req.Header.Set("Content-type", "application/json")
+	req.Header.Set("token", token)
+
+	q := req.URL.Query()
+
+	q.Set("feature_id", "1")
+	q.Set("tag_ids", "2")
+	q.Set("use_last_revision", "true")
+
+	req.URL.RawQuery = q.Encode()
+
+	routers := make(map[string]chi.Router)
+
+	routers["/user_banner"] = s.bannerHandler.Routes()
+
+	r := router.MakeRoutes("/test/api", routers)
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+
+	assertions.Equal(http.StatusForbidden, recorder.Result().StatusCode)
+
+	var problem handlerutils.Problem
 
-	s.NoError(json.NewDecoder(recorder.Body).Decode(&banner))
+	s.NoError(json.NewDecoder(recorder.Body).Decode(&problem))
 
-	assertions.Equal("title2", banner.Content.Title)
-	assertions.Equal("text2", banner.Content.Text)
-	assertions.Equal("http://url2.com", banner.Content.Url)
+	assertions.Equal("application/problem+json", recorder.Header().Get("Content-Type"))
+	assertions.Equal("forbidden", problem.Code)
+	assertions.Equal("banner is inactive", problem.Detail)
 }